@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+const (
+	defaultListen       = ":8080"
+	defaultMaxBodyBytes = 1 << 20
+)
+
+// config 是中转服务的配置文件，其中的 ${NAME} 会替换为环境变量，避免把密钥写进文件
+type config struct {
+	Listen       string        `yaml:"listen"`
+	MaxBodyBytes int64         `yaml:"max_body_bytes"`
+	Routes       []routeConfig `yaml:"routes"`
+}
+
+type routeConfig struct {
+	Path    string `yaml:"path"`
+	Webhook string `yaml:"webhook"`
+	Secret  string `yaml:"secret"`
+	// VerifySecret 不为空时，请求体必须携带飞书风格的 timestamp 和 sign 字段
+	VerifySecret string `yaml:"verify_secret"`
+	// MsgType 为 text、markdown、post 或 card；post 和 card 的模板需渲染为 JSON 或 YAML
+	MsgType  string `yaml:"msg_type"`
+	Title    string `yaml:"title"`
+	Template string `yaml:"template"`
+	Oversize string `yaml:"oversize"`
+}
+
+var msgTypes = map[string]bool{
+	"text":     true,
+	"markdown": true,
+	"post":     true,
+	"card":     true,
+}
+
+var oversizeModes = map[string]feishu.OversizeMode{
+	"":         feishu.OversizeStrict,
+	"strict":   feishu.OversizeStrict,
+	"split":    feishu.OversizeSplit,
+	"truncate": feishu.OversizeTruncate,
+}
+
+// 只替换 ${NAME}，模板中的 $i、$e 等变量保持不变
+var envRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func expandEnv(s string, getenv func(string) string) string {
+	return envRe.ReplaceAllStringFunc(s, func(m string) string {
+		return getenv(envRe.FindStringSubmatch(m)[1])
+	})
+}
+
+func loadConfig(path string, getenv func(string) string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config failed: %w", err)
+	}
+
+	var cfg config
+	if err := yaml.Unmarshal([]byte(expandEnv(string(data), getenv)), &cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s failed: %w", path, err)
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = defaultListen
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = defaultMaxBodyBytes
+	}
+	return &cfg, cfg.validate()
+}
+
+func (c *config) validate() error {
+	if len(c.Routes) == 0 {
+		return fmt.Errorf("config has no routes")
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range c.Routes {
+		prefix := fmt.Sprintf("routes[%d]", i)
+		switch {
+		case !strings.HasPrefix(r.Path, "/"):
+			return fmt.Errorf("%s: path %q must start with /", prefix, r.Path)
+		case r.Path == healthPath || r.Path == readyPath:
+			return fmt.Errorf("%s: path %q is reserved", prefix, r.Path)
+		case seen[r.Path]:
+			return fmt.Errorf("%s: duplicate path %q", prefix, r.Path)
+		case r.Webhook == "":
+			return fmt.Errorf("%s: webhook is empty", prefix)
+		case !msgTypes[r.MsgType]:
+			return fmt.Errorf("%s: unsupported msg_type %q", prefix, r.MsgType)
+		case r.Template == "":
+			return fmt.Errorf("%s: template is empty", prefix)
+		}
+		if _, ok := oversizeModes[r.Oversize]; !ok {
+			return fmt.Errorf("%s: unsupported oversize %q", prefix, r.Oversize)
+		}
+		seen[r.Path] = true
+	}
+	return nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := jsonMarshal(v)
+		return string(data), err
+	},
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+	"join": func(sep string, items []interface{}) string {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
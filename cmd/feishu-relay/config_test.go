@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "relay.yaml")
+		os.WriteFile(path, []byte(content), 0o644)
+		return path
+	}
+	getenv := func(key string) string {
+		return map[string]string{"FEISHU_SECRET": "from-env"}[key]
+	}
+
+	t.Run("默认值和环境变量", func(t *testing.T) {
+		path := write(t, `
+routes:
+  - path: /hooks/deploy
+    webhook: https://example.com/hook
+    secret: ${FEISHU_SECRET}
+    msg_type: text
+    template: "{{.message}}"
+`)
+		cfg, err := loadConfig(path, getenv)
+		if err != nil {
+			t.Fatalf("loadConfig() error: %v", err)
+		}
+		if cfg.Listen != defaultListen || cfg.MaxBodyBytes != defaultMaxBodyBytes {
+			t.Errorf("cfg = %+v", cfg)
+		}
+		if cfg.Routes[0].Secret != "from-env" {
+			t.Errorf("secret = %q, want from-env", cfg.Routes[0].Secret)
+		}
+	})
+
+	t.Run("模板变量保持不变", func(t *testing.T) {
+		template := `{{range $i, $e := .items}}{{$i}}={{$e}} {{end}}$HOME ${FEISHU_SECRET}`
+		path := write(t, `
+routes:
+  - path: /hooks/items
+    webhook: https://example.com/hook
+    msg_type: text
+    template: '`+template+`'
+`)
+		cfg, err := loadConfig(path, getenv)
+		if err != nil {
+			t.Fatalf("loadConfig() error: %v", err)
+		}
+		want := `{{range $i, $e := .items}}{{$i}}={{$e}} {{end}}$HOME from-env`
+		if got := cfg.Routes[0].Template; got != want {
+			t.Errorf("template = %q, want %q", got, want)
+		}
+	})
+
+	invalid := []struct {
+		name   string
+		routes string
+		want   string
+	}{
+		{"没有路由", "", "no routes"},
+		{"路径格式", "  - {path: hooks, webhook: x, msg_type: text, template: x}", "must start with /"},
+		{"保留路径", "  - {path: /healthz, webhook: x, msg_type: text, template: x}", "reserved"},
+		{"重复路径", "  - {path: /a, webhook: x, msg_type: text, template: x}\n  - {path: /a, webhook: x, msg_type: text, template: x}", "duplicate"},
+		{"缺少 webhook", "  - {path: /a, msg_type: text, template: x}", "webhook"},
+		{"消息类型", "  - {path: /a, webhook: x, msg_type: image, template: x}", "msg_type"},
+		{"缺少模板", "  - {path: /a, webhook: x, msg_type: text}", "template"},
+		{"超限策略", "  - {path: /a, webhook: x, msg_type: text, template: x, oversize: drop}", "oversize"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfig(write(t, "routes:\n"+tt.routes+"\n"), getenv)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadConfig() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Command feishu-relay 接收任意 JSON 的 HTTP 请求，按路由模板渲染为飞书消息后签名发送。
+//
+// 用法：
+//
+//	feishu-relay -config relay.yaml
+//
+// 配置示例：
+//
+//	listen: ":8080"
+//	max_body_bytes: 1048576
+//	routes:
+//	  - path: /hooks/deploy
+//	    webhook: https://open.feishu.cn/open-apis/bot/v2/hook/xxx
+//	    secret: ${FEISHU_SECRET}
+//	    msg_type: markdown
+//	    title: "部署 {{.service}}"
+//	    template: |
+//	      **{{.service}}** 已部署到 {{.env}}，版本 {{.version}}
+//
+// /healthz 用于存活检查，/readyz 在服务关闭过程中返回 503。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	maxSignSkew     = time.Hour
+	shutdownTimeout = 30 * time.Second
+)
+
+func main() {
+	configPath := flag.String("config", "relay.yaml", "config file")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "feishu-relay: ", log.LstdFlags)
+
+	cfg, err := loadConfig(*configPath, os.Getenv)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	r, err := newRelay(cfg, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	server := &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           r.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		r.draining.Store(true)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Printf("shutdown: %v", err)
+		}
+	}()
+
+	logger.Printf("listening on %s with %d routes", cfg.Listen, len(cfg.Routes))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(err)
+	}
+}
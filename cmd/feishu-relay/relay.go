@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+const (
+	healthPath = "/healthz"
+	readyPath  = "/readyz"
+)
+
+type route struct {
+	config       routeConfig
+	title        *template.Template
+	body         *template.Template
+	sender       feishu.Sender
+	verifySecret string
+}
+
+// relay 把收到的任意 JSON 按路由模板渲染为飞书消息并签名发送，webhook 密钥只保存在中转服务中
+type relay struct {
+	maxBodyBytes int64
+	routes       map[string]*route
+	draining     atomic.Bool
+	logger       *log.Logger
+}
+
+func newRelay(cfg *config, logger *log.Logger) (*relay, error) {
+	r := &relay{
+		maxBodyBytes: cfg.MaxBodyBytes,
+		routes:       make(map[string]*route, len(cfg.Routes)),
+		logger:       logger,
+	}
+
+	for _, rc := range cfg.Routes {
+		body, err := parseTemplate(rc.Path, rc.Template)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: parse template failed: %w", rc.Path, err)
+		}
+		title, err := parseTemplate(rc.Path+"#title", rc.Title)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: parse title failed: %w", rc.Path, err)
+		}
+
+		client := feishu.NewClientWithOptions(rc.Webhook,
+			feishu.WithSecret(rc.Secret),
+			feishu.WithRetryPolicy(feishu.DefaultRetryPolicy()),
+			feishu.WithRateLimit(feishu.RateLimitBlock),
+			feishu.WithOversizeMode(oversizeModes[rc.Oversize]),
+		)
+		r.routes[rc.Path] = &route{
+			config:       rc,
+			title:        title,
+			body:         body,
+			sender:       client,
+			verifySecret: rc.VerifySecret,
+		}
+	}
+	return r, nil
+}
+
+func (r *relay) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthPath, func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc(readyPath, func(w http.ResponseWriter, req *http.Request) {
+		if r.draining.Load() {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+	})
+	for path, rt := range r.routes {
+		mux.Handle(path, r.serveRoute(rt))
+	}
+	return mux
+}
+
+func (r *relay) serveRoute(rt *route) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if req.ContentLength > r.maxBodyBytes {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+
+		payload, err := decodePayload(http.MaxBytesReader(w, req.Body, r.maxBodyBytes))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+			return
+		}
+
+		if rt.verifySecret != "" {
+			if err := verifyPayload(rt.verifySecret, payload); err != nil {
+				writeError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+		}
+
+		message, err := rt.render(payload)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		if err := rt.sender.SendMessageContext(req.Context(), message); err != nil {
+			r.logger.Printf("route %s: send failed: %v", rt.config.Path, err)
+			writeError(w, sendStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+	}
+}
+
+// decodePayload 解析请求体中唯一的 JSON 值，之后还有其它内容时返回错误
+func decodePayload(body io.Reader) (interface{}, error) {
+	var payload interface{}
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, err
+		}
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return payload, nil
+}
+
+// verifyPayload 校验请求体中的 timestamp 和 sign 字段，时间戳需在一小时内
+func verifyPayload(secret string, payload interface{}) error {
+	fields, _ := payload.(map[string]interface{})
+	timestamp, _ := fields["timestamp"].(string)
+	if n, ok := fields["timestamp"].(json.Number); ok {
+		timestamp = n.String()
+	}
+	sign, _ := fields["sign"].(string)
+	if timestamp == "" || sign == "" {
+		return fmt.Errorf("missing timestamp or sign")
+	}
+	return feishu.VerifySign(secret, timestamp, sign, maxSignSkew)
+}
+
+func (rt *route) render(payload interface{}) (*feishu.Message, error) {
+	body, err := execute(rt.body, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var message *feishu.Message
+	switch rt.config.MsgType {
+	case "text":
+		message = feishu.NewTextMessage(strings.TrimSpace(body))
+	case "markdown":
+		title, err := execute(rt.title, payload)
+		if err != nil {
+			return nil, err
+		}
+		post := feishu.MarkdownToPost(strings.TrimSpace(title), body)
+		message = feishu.NewRichTextMessage(post.Title, post.Content)
+	case "post":
+		message, err = decodeMessage(feishu.MessageTypeRichText, body)
+	case "card":
+		message, err = decodeMessage(feishu.MessageTypeInteractive, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := message.Validate(); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func execute(t *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template failed: %w", err)
+	}
+	// 请求体解码为 map，缺失的字段会被渲染为 "<no value>"，这里按空值处理
+	return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+}
+
+// decodeMessage 把模板渲染出的 JSON 或 YAML 解码为 msgType 对应的消息
+func decodeMessage(msgType feishu.MessageType, body string) (*feishu.Message, error) {
+	var content interface{}
+	if err := yaml.Unmarshal([]byte(body), &content); err != nil {
+		return nil, fmt.Errorf("parse rendered %s failed: %w", msgType, err)
+	}
+
+	data, err := jsonMarshal(map[string]interface{}{"msg_type": msgType, "content": content})
+	if err != nil {
+		return nil, fmt.Errorf("encode rendered %s failed: %w", msgType, err)
+	}
+
+	var message feishu.Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func jsonMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func sendStatus(err error) int {
+	var validation feishu.ValidationErrors
+	switch {
+	case errors.As(err, &validation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, feishu.ErrMessageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, feishu.ErrRateLimited):
+		return http.StatusTooManyRequests
+	}
+	return http.StatusBadGateway
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
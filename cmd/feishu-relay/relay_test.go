@@ -0,0 +1,173 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+	"github.com/straubel/feishu-webhook/common/feishu/feishutest"
+)
+
+func newTestRelay(t *testing.T, routes ...routeConfig) (*httptest.Server, *feishutest.Server) {
+	t.Helper()
+	feishuServer := feishutest.NewServer(feishutest.WithSecret("secret"))
+	t.Cleanup(feishuServer.Close)
+
+	for i := range routes {
+		routes[i].Webhook = feishuServer.URL
+		routes[i].Secret = "secret"
+	}
+	cfg := &config{MaxBodyBytes: 1024, Routes: routes}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate() error: %v", err)
+	}
+
+	r, err := newRelay(cfg, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("newRelay() error: %v", err)
+	}
+	server := httptest.NewServer(r.handler())
+	t.Cleanup(server.Close)
+	return server, feishuServer
+}
+
+func post(t *testing.T, url, body string) (int, string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestRelayRoutes(t *testing.T) {
+	relay, feishuServer := newTestRelay(t,
+		routeConfig{Path: "/text", MsgType: "text", Template: "{{.service}} {{upper .status}}"},
+		routeConfig{Path: "/markdown", MsgType: "markdown", Title: "部署 {{.service}}", Template: "版本 **{{.version}}**"},
+		routeConfig{Path: "/card", MsgType: "card", Template: `{"header":{"title":{"tag":"plain_text","content":{{json .service}}}},"elements":[{"tag":"hr"}]}`},
+		routeConfig{Path: "/post", MsgType: "post", Template: "post:\n  zh_cn:\n    title: {{.service}}\n    content: [[{tag: text, text: hi}]]\n"},
+	)
+
+	payload := `{"service":"api","status":"ok","version":"v1.2.0"}`
+	for _, path := range []string{"/text", "/markdown", "/card", "/post"} {
+		if status, body := post(t, relay.URL+path, payload); status != http.StatusOK {
+			t.Fatalf("POST %s = %d %s", path, status, body)
+		}
+	}
+
+	messages := feishuServer.Messages()
+	if len(messages) != 4 {
+		t.Fatalf("messages = %d, want 4", len(messages))
+	}
+	if text := messages[0].Content.(*feishu.TextContent).Text; text != "api OK" {
+		t.Errorf("text = %q", text)
+	}
+	if title := messages[1].Content.(*feishu.RichTextContent).Post.ZhCn.Title; title != "部署 api" {
+		t.Errorf("markdown title = %q", title)
+	}
+	if title := messages[2].Content.(*feishu.InteractiveContent).Header.Title.Content; title != "api" {
+		t.Errorf("card title = %q", title)
+	}
+	if title := messages[3].Content.(*feishu.RichTextContent).Post.ZhCn.Title; title != "api" {
+		t.Errorf("post title = %q", title)
+	}
+}
+
+func TestRelayRejects(t *testing.T) {
+	relay, feishuServer := newTestRelay(t,
+		routeConfig{Path: "/text", MsgType: "text", Template: "{{.message}}"},
+		routeConfig{Path: "/card", MsgType: "card", Template: "{{.message}}"},
+		routeConfig{Path: "/signed", MsgType: "text", Template: "{{.message}}", VerifySecret: "inbound"},
+	)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"无效 JSON", "/text", `{"message":`, http.StatusBadRequest},
+		{"JSON 之后还有内容", "/text", `{"message":"hi"} garbage`, http.StatusBadRequest},
+		{"多个 JSON 值", "/text", `{"message":"hi"}{"message":"again"}`, http.StatusBadRequest},
+		{"请求体过大", "/text", `{"message":"` + strings.Repeat("a", 2048) + `"}`, http.StatusRequestEntityTooLarge},
+		{"渲染为空消息", "/text", `{}`, http.StatusUnprocessableEntity},
+		{"渲染结果不是卡片", "/card", `{"message":"{"}`, http.StatusUnprocessableEntity},
+		{"缺少签名", "/signed", `{"message":"hi"}`, http.StatusUnauthorized},
+		{"签名错误", "/signed", `{"message":"hi","timestamp":"` + strconv.FormatInt(time.Now().Unix(), 10) + `","sign":"bad"}`, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, body := post(t, relay.URL+tt.path, tt.body); status != tt.want {
+				t.Errorf("status = %d, want %d, body = %s", status, tt.want, body)
+			}
+		})
+	}
+
+	t.Run("只接受 POST", func(t *testing.T) {
+		resp, err := http.Get(relay.URL + "/text")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want 405", resp.StatusCode)
+		}
+	})
+
+	t.Run("签名正确", func(t *testing.T) {
+		ts := time.Now().Unix()
+		sign, _ := feishu.GenSign("inbound", ts)
+		body := `{"message":"hi","timestamp":` + strconv.FormatInt(ts, 10) + `,"sign":"` + sign + `"}`
+		if status, body := post(t, relay.URL+"/signed", body); status != http.StatusOK {
+			t.Errorf("status = %d, body = %s", status, body)
+		}
+	})
+
+	t.Run("飞书返回错误", func(t *testing.T) {
+		feishuServer.Respond(feishutest.ErrorCode(feishutest.CodeKeywordMismatch, "Key Words Not Found"))
+		if status, body := post(t, relay.URL+"/text", `{"message":"hi"}`); status != http.StatusBadGateway {
+			t.Errorf("status = %d, want 502, body = %s", status, body)
+		}
+	})
+}
+
+func TestRelayHealth(t *testing.T) {
+	cfg := &config{MaxBodyBytes: 1024, Routes: []routeConfig{{Path: "/text", Webhook: "http://127.0.0.1:1", MsgType: "text", Template: "x"}}}
+	r, err := newRelay(cfg, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("newRelay() error: %v", err)
+	}
+	server := httptest.NewServer(r.handler())
+	defer server.Close()
+
+	get := func(path string) int {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := get(healthPath); status != http.StatusOK {
+		t.Errorf("healthz = %d", status)
+	}
+	if status := get(readyPath); status != http.StatusOK {
+		t.Errorf("readyz = %d", status)
+	}
+	r.draining.Store(true)
+	if status := get(readyPath); status != http.StatusServiceUnavailable {
+		t.Errorf("readyz while draining = %d, want 503", status)
+	}
+	if status := get(healthPath); status != http.StatusOK {
+		t.Errorf("healthz while draining = %d", status)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envWebhook = "FEISHU_WEBHOOK_URL"
+	envSecret  = "FEISHU_WEBHOOK_SECRET"
+	envConfig  = "FEISHU_WEBHOOK_CONFIG"
+)
+
+// config 是配置文件的内容，YAML 和 JSON 格式均可
+type config struct {
+	Webhook string `yaml:"webhook"`
+	Secret  string `yaml:"secret"`
+}
+
+// resolveConfig 按 命令行参数 > 环境变量 > 配置文件 的优先级确定 webhook 地址和密钥
+func resolveConfig(flags config, path string, getenv func(string) string) (config, error) {
+	file, err := loadConfig(path, getenv)
+	if err != nil {
+		return config{}, err
+	}
+
+	return config{
+		Webhook: firstNonEmpty(flags.Webhook, getenv(envWebhook), file.Webhook),
+		Secret:  firstNonEmpty(flags.Secret, getenv(envSecret), file.Secret),
+	}, nil
+}
+
+// loadConfig 读取 path 指定的配置文件；path 为空时依次尝试环境变量和默认位置，默认位置不存在时不报错
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	explicit := true
+	if path == "" {
+		path = getenv(envConfig)
+	}
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return config{}, nil
+		}
+		path = filepath.Join(dir, "feishu-webhook", "config.yaml")
+		explicit = false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return config{}, nil
+		}
+		return config{}, fmt.Errorf("read config failed: %w", err)
+	}
+
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return config{}, fmt.Errorf("parse config %s failed: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
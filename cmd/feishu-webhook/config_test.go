@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte("webhook: https://file.example.com\nsecret: file-secret\n"), 0o644)
+
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	t.Run("配置文件", func(t *testing.T) {
+		cfg, err := resolveConfig(config{}, path, getenv)
+		if err != nil {
+			t.Fatalf("resolveConfig() error: %v", err)
+		}
+		if cfg.Webhook != "https://file.example.com" || cfg.Secret != "file-secret" {
+			t.Errorf("cfg = %+v", cfg)
+		}
+	})
+
+	t.Run("环境变量优先于配置文件", func(t *testing.T) {
+		env[envWebhook] = "https://env.example.com"
+		defer delete(env, envWebhook)
+
+		cfg, _ := resolveConfig(config{}, path, getenv)
+		if cfg.Webhook != "https://env.example.com" || cfg.Secret != "file-secret" {
+			t.Errorf("cfg = %+v", cfg)
+		}
+	})
+
+	t.Run("参数优先于环境变量", func(t *testing.T) {
+		env[envSecret] = "env-secret"
+		defer delete(env, envSecret)
+
+		cfg, _ := resolveConfig(config{Secret: "flag-secret"}, path, getenv)
+		if cfg.Secret != "flag-secret" {
+			t.Errorf("cfg = %+v", cfg)
+		}
+	})
+
+	t.Run("通过环境变量指定 JSON 配置文件", func(t *testing.T) {
+		jsonPath := filepath.Join(dir, "config.json")
+		os.WriteFile(jsonPath, []byte(`{"webhook": "https://json.example.com"}`), 0o644)
+		env[envConfig] = jsonPath
+		defer delete(env, envConfig)
+
+		cfg, err := resolveConfig(config{}, "", getenv)
+		if err != nil {
+			t.Fatalf("resolveConfig() error: %v", err)
+		}
+		if cfg.Webhook != "https://json.example.com" {
+			t.Errorf("cfg = %+v", cfg)
+		}
+	})
+
+	t.Run("指定的配置文件不存在", func(t *testing.T) {
+		if _, err := resolveConfig(config{}, filepath.Join(dir, "missing.yaml"), getenv); err == nil {
+			t.Error("resolveConfig() should fail for a missing explicit config")
+		}
+	})
+}
@@ -0,0 +1,256 @@
+// Command feishu-webhook 从命令行向飞书自定义机器人发送消息，便于在 shell 脚本和 CI 中使用。
+//
+// 用法：
+//
+//	feishu-webhook send <text|post|image|card|share-chat> [flags] [body...]
+//
+// webhook 地址和密钥依次从 --webhook/--secret、环境变量 FEISHU_WEBHOOK_URL/FEISHU_WEBHOOK_SECRET
+// 和配置文件（--config、FEISHU_WEBHOOK_CONFIG 或 $XDG_CONFIG_HOME/feishu-webhook/config.yaml）中读取。
+// 消息正文依次取自命令行参数、--file 和标准输入。
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+// 退出码，便于脚本区分失败原因
+const (
+	exitOK             = 0
+	exitFailure        = 1
+	exitUsage          = 2
+	exitInvalidMessage = 3
+	exitTooLarge       = 4
+	exitSignature      = 5
+	exitKeyword        = 6
+	exitRateLimited    = 7
+	exitIPNotAllowed   = 8
+	exitParamInvalid   = 9
+	exitNetwork        = 10
+)
+
+const usage = `Usage:
+  feishu-webhook send <text|post|image|card|share-chat> [flags] [body...]
+
+Message body:
+  text        plain text
+  post        Markdown, converted to a rich text post (JSON/YAML post content with --format)
+  image       image_key
+  card        card JSON/YAML (card 1.0, card 2.0 or template card)
+  share-chat  share_chat_id
+
+The body is read from the arguments, then --file ("-" for stdin), then stdin.
+
+Exit codes:
+  0 sent, 1 other error, 2 usage error, 3 invalid message, 4 message too large,
+  5 signature invalid, 6 keyword mismatch, 7 rate limited, 8 IP not allowed,
+  9 param invalid, 10 network error
+
+Flags:
+`
+
+type sendFlags struct {
+	webhook  string
+	secret   string
+	config   string
+	file     string
+	format   string
+	title    string
+	dryRun   bool
+	timeout  time.Duration
+	retries  int
+	oversize string
+}
+
+func main() {
+	var stdin io.Reader = os.Stdin
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		stdin = nil
+	}
+	os.Exit(run(os.Args[1:], stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) < 2 || args[0] != "send" {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	kind := args[1]
+	if !messageKinds[kind] {
+		fmt.Fprintf(stderr, "unknown message type %q\n\n%s", kind, usage)
+		return exitUsage
+	}
+
+	var f sendFlags
+	fs := flag.NewFlagSet("feishu-webhook send "+kind, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&f.webhook, "webhook", "", "webhook URL (env "+envWebhook+")")
+	fs.StringVar(&f.secret, "secret", "", "signing secret (env "+envSecret+")")
+	fs.StringVar(&f.config, "config", "", "config file with webhook and secret (env "+envConfig+")")
+	fs.StringVar(&f.file, "file", "", `read the message body from a file, "-" for stdin`)
+	fs.StringVar(&f.format, "format", "", "body format for post: markdown, json or yaml (default from --file extension)")
+	fs.StringVar(&f.title, "title", "", "post title (default: the leading h1 of the Markdown)")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "print the signed request instead of sending it")
+	fs.DurationVar(&f.timeout, "timeout", 10*time.Second, "request timeout")
+	fs.IntVar(&f.retries, "retries", 0, "retry failed requests up to this many times")
+	fs.StringVar(&f.oversize, "oversize", "strict", "oversized text and post messages: strict, split or truncate")
+
+	positional, err := parseFlags(fs, args[2:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	oversize, ok := oversizeModes[f.oversize]
+	if !ok {
+		fmt.Fprintf(stderr, "invalid --oversize %q\n", f.oversize)
+		return exitUsage
+	}
+
+	body, err := readBody(positional, f.file, stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	message, err := buildMessage(kind, body, messageOptions{title: f.title, format: f.format, file: f.file})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInvalidMessage
+	}
+
+	cfg, err := resolveConfig(config{Webhook: f.webhook, Secret: f.secret}, f.config, getenv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	if f.dryRun {
+		if err := message.Validate(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitCode(err)
+		}
+		return printRequest(stdout, stderr, cfg.Secret, message)
+	}
+
+	if cfg.Webhook == "" {
+		fmt.Fprintf(stderr, "webhook URL is required: use --webhook, %s or a config file\n", envWebhook)
+		return exitUsage
+	}
+
+	opts := []feishu.ClientOption{
+		feishu.WithSecret(cfg.Secret),
+		feishu.WithTimeout(f.timeout),
+		feishu.WithOversizeMode(oversize),
+	}
+	if f.retries > 0 {
+		policy := feishu.DefaultRetryPolicy()
+		policy.MaxAttempts = f.retries + 1
+		opts = append(opts, feishu.WithRetryPolicy(policy))
+	}
+
+	client := feishu.NewClientWithOptions(cfg.Webhook, opts...)
+	if err := client.SendMessageContext(context.Background(), message); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+var messageKinds = map[string]bool{
+	"text":       true,
+	"post":       true,
+	"image":      true,
+	"card":       true,
+	"share-chat": true,
+}
+
+var oversizeModes = map[string]feishu.OversizeMode{
+	"strict":   feishu.OversizeStrict,
+	"split":    feishu.OversizeSplit,
+	"truncate": feishu.OversizeTruncate,
+}
+
+// parseFlags 允许参数和正文交替出现，例如 send text "hello" --dry-run
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func printRequest(stdout, stderr io.Writer, secret string, message *feishu.Message) int {
+	request := &feishu.WebhookRequest{
+		MsgType: string(message.MsgType),
+		Content: message.Content,
+	}
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		sign, err := feishu.GenSign(secret, timestamp)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		request.Timestamp = fmt.Sprintf("%d", timestamp)
+		request.Sign = sign
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(request); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func exitCode(err error) int {
+	var validation feishu.ValidationErrors
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &validation):
+		return exitInvalidMessage
+	case errors.Is(err, feishu.ErrMessageTooLarge):
+		return exitTooLarge
+	case errors.Is(err, feishu.ErrSignatureInvalid):
+		return exitSignature
+	case errors.Is(err, feishu.ErrKeywordMismatch):
+		return exitKeyword
+	case errors.Is(err, feishu.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, feishu.ErrIPNotAllowed):
+		return exitIPNotAllowed
+	case errors.Is(err, feishu.ErrParamInvalid):
+		return exitParamInvalid
+	case errors.As(err, &urlErr), errors.Is(err, context.DeadlineExceeded):
+		return exitNetwork
+	}
+	return exitFailure
+}
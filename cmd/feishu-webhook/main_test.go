@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+	"github.com/straubel/feishu-webhook/common/feishu/feishutest"
+)
+
+func noEnv(string) string { return "" }
+
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	// 避免读取本机的默认配置文件
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	var in io.Reader
+	if stdin != "" {
+		in = strings.NewReader(stdin)
+	}
+	code := run(args, in, &stdout, &stderr, noEnv)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestSend(t *testing.T) {
+	server := feishutest.NewServer(feishutest.WithSecret("secret"))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		check func(t *testing.T, message *feishu.Message)
+	}{
+		{
+			name: "文本来自参数",
+			args: []string{"send", "text", "--webhook", server.URL, "--secret", "secret", "hello", "world"},
+			check: func(t *testing.T, message *feishu.Message) {
+				if text := message.Content.(*feishu.TextContent).Text; text != "hello world" {
+					t.Errorf("text = %q", text)
+				}
+			},
+		},
+		{
+			name:  "文本来自标准输入",
+			stdin: "from stdin\n",
+			args:  []string{"send", "text", "--webhook", server.URL, "--secret", "secret"},
+			check: func(t *testing.T, message *feishu.Message) {
+				if text := message.Content.(*feishu.TextContent).Text; text != "from stdin" {
+					t.Errorf("text = %q", text)
+				}
+			},
+		},
+		{
+			name:  "Markdown 转富文本",
+			stdin: "# 部署完成\n\n版本 **v1.2.0**",
+			args:  []string{"send", "post", "--webhook", server.URL, "--secret", "secret"},
+			check: func(t *testing.T, message *feishu.Message) {
+				post := message.Content.(*feishu.RichTextContent).Post.ZhCn
+				if post.Title != "部署完成" || len(post.Content) != 1 {
+					t.Errorf("post = %+v", post)
+				}
+			},
+		},
+		{
+			name: "图片",
+			args: []string{"send", "image", "--webhook", server.URL, "--secret", "secret", "img_key"},
+			check: func(t *testing.T, message *feishu.Message) {
+				if key := message.Content.(*feishu.ImageContent).ImageKey; key != "img_key" {
+					t.Errorf("image_key = %q", key)
+				}
+			},
+		},
+		{
+			name: "群名片",
+			args: []string{"send", "share-chat", "--webhook", server.URL, "--secret", "secret", "oc_123"},
+			check: func(t *testing.T, message *feishu.Message) {
+				if id := message.Content.(*feishu.ShareChatContent).ShareChatId; id != "oc_123" {
+					t.Errorf("share_chat_id = %q", id)
+				}
+			},
+		},
+		{
+			name:  "YAML 卡片",
+			stdin: "header:\n  title:\n    tag: plain_text\n    content: 标题\nelements:\n  - tag: hr\n",
+			args:  []string{"send", "card", "--webhook", server.URL, "--secret", "secret"},
+			check: func(t *testing.T, message *feishu.Message) {
+				card := message.Content.(*feishu.InteractiveContent)
+				if card.Header.Title.Content != "标题" || len(card.Elements) != 1 {
+					t.Errorf("card = %+v", card)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.Reset()
+			code, _, stderr := runCLI(t, tt.stdin, tt.args...)
+			if code != exitOK {
+				t.Fatalf("exit = %d, stderr = %s", code, stderr)
+			}
+			messages := server.Messages()
+			if len(messages) != 1 {
+				t.Fatalf("messages = %d, want 1", len(messages))
+			}
+			tt.check(t, messages[0])
+		})
+	}
+
+	t.Run("从 JSON 文件读取富文本", func(t *testing.T) {
+		server.Reset()
+		path := filepath.Join(t.TempDir(), "post.json")
+		os.WriteFile(path, []byte(`{"post":{"en_us":{"title":"Report","content":[[{"tag":"text","text":"ok"}]]}}}`), 0o644)
+
+		code, _, stderr := runCLI(t, "", "send", "post", "--webhook", server.URL, "--secret", "secret", "--file", path)
+		if code != exitOK {
+			t.Fatalf("exit = %d, stderr = %s", code, stderr)
+		}
+		if post := server.Messages()[0].Content.(*feishu.RichTextContent).Post; post.EnUs == nil || post.EnUs.Title != "Report" {
+			t.Errorf("post = %+v", post)
+		}
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	code, stdout, stderr := runCLI(t, "", "send", "text", "--dry-run", "--secret", "secret", "hello <world>")
+	if code != exitOK {
+		t.Fatalf("exit = %d, stderr = %s", code, stderr)
+	}
+
+	var request feishu.WebhookRequest
+	if err := json.Unmarshal([]byte(stdout), &request); err != nil {
+		t.Fatalf("stdout is not a request: %v\n%s", err, stdout)
+	}
+	if err := feishu.VerifySign("secret", request.Timestamp, request.Sign, 0); err != nil {
+		t.Errorf("VerifySign() = %v", err)
+	}
+	if text := request.Content.(*feishu.TextContent).Text; text != "hello <world>" {
+		t.Errorf("text = %q", text)
+	}
+	if !strings.Contains(stdout, "<world>") {
+		t.Error("dry run output should not escape HTML")
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	server := feishutest.NewServer(feishutest.WithSecret("secret"), feishutest.WithKeywords("告警"))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		respond []feishutest.Response
+		args    []string
+		want    int
+	}{
+		{"缺少子命令", nil, []string{"send"}, exitUsage},
+		{"未知消息类型", nil, []string{"send", "audio", "x"}, exitUsage},
+		{"未知参数", nil, []string{"send", "text", "--unknown", "x"}, exitUsage},
+		{"缺少 webhook", nil, []string{"send", "text", "x"}, exitUsage},
+		{"缺少正文", nil, []string{"send", "text", "--webhook", server.URL}, exitUsage},
+		{"无效消息", nil, []string{"send", "image", "--webhook", server.URL, " "}, exitInvalidMessage},
+		{"无效卡片", nil, []string{"send", "card", "--webhook", server.URL, "{"}, exitInvalidMessage},
+		{"消息过大", nil, []string{"send", "text", "--webhook", server.URL, strings.Repeat("告警", 10000)}, exitTooLarge},
+		{"签名错误", nil, []string{"send", "text", "--webhook", server.URL, "--secret", "wrong", "告警"}, exitSignature},
+		{"关键词不匹配", nil, []string{"send", "text", "--webhook", server.URL, "--secret", "secret", "hello"}, exitKeyword},
+		{"频率限制", []feishutest.Response{feishutest.ErrorCode(feishutest.CodeRateLimited, "limited")},
+			[]string{"send", "text", "--webhook", server.URL, "--secret", "secret", "告警"}, exitRateLimited},
+		{"IP 不在白名单", []feishutest.Response{feishutest.ErrorCode(feishutest.CodeIPNotAllowed, "ip not allowed")},
+			[]string{"send", "text", "--webhook", server.URL, "--secret", "secret", "告警"}, exitIPNotAllowed},
+		{"参数错误", []feishutest.Response{feishutest.ErrorCode(feishutest.CodeParamInvalid, "param invalid")},
+			[]string{"send", "text", "--webhook", server.URL, "--secret", "secret", "告警"}, exitParamInvalid},
+		{"网络错误", nil, []string{"send", "text", "--webhook", "http://127.0.0.1:1", "告警"}, exitNetwork},
+		{"重试后成功", []feishutest.Response{{Status: 500}},
+			[]string{"send", "text", "--webhook", server.URL, "--secret", "secret", "--retries", "1", "告警"}, exitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.Respond(tt.respond...)
+			if code, _, stderr := runCLI(t, "", tt.args...); code != tt.want {
+				t.Errorf("exit = %d, want %d, stderr = %s", code, tt.want, stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+// readBody 读取消息正文：优先使用命令行参数，其次是 --file 指定的文件（"-" 表示标准输入），最后是标准输入
+func readBody(args []string, file string, stdin io.Reader) (string, error) {
+	if len(args) > 0 && !(len(args) == 1 && args[0] == "-") {
+		return strings.Join(args, " "), nil
+	}
+
+	if file != "" && file != "-" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("read message file failed: %w", err)
+		}
+		return string(data), nil
+	}
+
+	if stdin == nil {
+		return "", fmt.Errorf("no message body: pass it as arguments, --file or stdin")
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", fmt.Errorf("read stdin failed: %w", err)
+	}
+	return string(data), nil
+}
+
+// isStructured 判断正文是否应按 JSON/YAML 解析
+func isStructured(format, file string) bool {
+	switch format {
+	case "json", "yaml":
+		return true
+	case "":
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".json", ".yaml", ".yml":
+			return true
+		}
+	}
+	return false
+}
+
+// decodeContent 把 JSON 或 YAML 正文解码为对应 msgType 的消息
+func decodeContent(msgType feishu.MessageType, body string) (*feishu.Message, error) {
+	var content interface{}
+	if err := yaml.Unmarshal([]byte(body), &content); err != nil {
+		return nil, fmt.Errorf("parse %s content failed: %w", msgType, err)
+	}
+	if content == nil {
+		return nil, fmt.Errorf("%s content is empty", msgType)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"msg_type": msgType, "content": content})
+	if err != nil {
+		return nil, fmt.Errorf("encode %s content failed: %w", msgType, err)
+	}
+
+	var message feishu.Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+type messageOptions struct {
+	title  string
+	format string
+	file   string
+}
+
+// buildMessage 根据子命令构造消息
+func buildMessage(kind string, body string, opts messageOptions) (*feishu.Message, error) {
+	structured := isStructured(opts.format, opts.file)
+
+	switch kind {
+	case "text":
+		if structured {
+			return decodeContent(feishu.MessageTypeText, body)
+		}
+		return feishu.NewTextMessage(strings.TrimRight(body, "\n")), nil
+	case "post":
+		if structured {
+			return decodeContent(feishu.MessageTypeRichText, body)
+		}
+		post := feishu.MarkdownToPost(opts.title, body)
+		return feishu.NewRichTextMessage(post.Title, post.Content), nil
+	case "image":
+		return feishu.NewImageMessage(strings.TrimSpace(body)), nil
+	case "share-chat":
+		return feishu.NewShareChatMessage(strings.TrimSpace(body)), nil
+	case "card":
+		return decodeContent(feishu.MessageTypeInteractive, body)
+	}
+	return nil, fmt.Errorf("unknown message type %q", kind)
+}
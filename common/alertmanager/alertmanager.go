@@ -0,0 +1,274 @@
+// Package alertmanager 接收 Prometheus Alertmanager 的 webhook 通知，并把告警分组渲染为飞书卡片
+package alertmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+	"github.com/straubel/feishu-webhook/common/internal/alertcard"
+)
+
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+
+	DefaultMaxAlerts    = 10
+	DefaultMaxBodyBytes = 4 << 20
+)
+
+var ErrNoAlerts = errors.New("alertmanager: payload has no alerts")
+
+// Payload 是 Alertmanager webhook 第 4 版的请求体
+type Payload struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+}
+
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+type Option func(*Handler)
+
+// WithMaxAlerts 限制每张卡片展示的告警数量，避免超过飞书的消息大小限制
+func WithMaxAlerts(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxAlerts = n
+		}
+	}
+}
+
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
+}
+
+// WithLocation 设置卡片中时间的显示时区
+func WithLocation(loc *time.Location) Option {
+	return func(h *Handler) {
+		h.location = loc
+	}
+}
+
+func WithErrorLog(logger *log.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// Handler 是 Alertmanager webhook receiver，收到的每个告警分组发送为一张卡片
+type Handler struct {
+	sender       feishu.Sender
+	maxAlerts    int
+	maxBodyBytes int64
+	location     *time.Location
+	logger       *log.Logger
+}
+
+func NewHandler(sender feishu.Sender, opts ...Option) *Handler {
+	h := &Handler{
+		sender:       sender,
+		maxAlerts:    DefaultMaxAlerts,
+		maxBodyBytes: DefaultMaxBodyBytes,
+		location:     time.Local,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload Payload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes)).Decode(&payload); err != nil {
+		http.Error(w, "invalid alertmanager payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if payload.Version != "4" {
+		http.Error(w, fmt.Sprintf("unsupported alertmanager payload version %q", payload.Version), http.StatusBadRequest)
+		return
+	}
+	// 空分组没有可以渲染的内容；返回 4xx 会被 Alertmanager 记为通知失败
+	if len(payload.Alerts) == 0 && payload.TruncatedAlerts == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	message, err := h.Render(&payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err := h.sender.SendMessageContext(r.Context(), message); err != nil {
+		if h.logger != nil {
+			h.logger.Printf("alertmanager: send group %s failed: %v", payload.GroupKey, err)
+		}
+		// 返回 5xx 让 Alertmanager 重试
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Render 把告警分组渲染为卡片：触发中的告警使用红色标题，全部恢复后使用绿色标题
+func (h *Handler) Render(p *Payload) (*feishu.Message, error) {
+	if len(p.Alerts) == 0 && p.TruncatedAlerts == 0 {
+		return nil, ErrNoAlerts
+	}
+
+	var firing, resolved []Alert
+	for _, alert := range p.Alerts {
+		if alert.Status == StatusResolved {
+			resolved = append(resolved, alert)
+		} else {
+			firing = append(firing, alert)
+		}
+	}
+
+	template := "red"
+	if p.Status == StatusResolved {
+		template = "green"
+	}
+
+	builder := feishu.NewCardBuilder().
+		Config(feishu.CreateCardConfig(true)).
+		Header(title(p, len(firing)), template)
+
+	if summary := firstNonEmpty(p.CommonAnnotations["summary"], p.CommonAnnotations["description"]); summary != "" {
+		builder.Markdown(summary)
+	}
+
+	shown := 0
+	for _, group := range []struct {
+		name   string
+		alerts []Alert
+	}{{"触发中", firing}, {"已恢复", resolved}} {
+		if len(group.alerts) == 0 {
+			continue
+		}
+		builder.Hr().Markdown(fmt.Sprintf("**%s（%d）**", group.name, len(group.alerts)))
+		for _, alert := range group.alerts {
+			if shown == h.maxAlerts {
+				break
+			}
+			h.addAlert(builder, p, alert)
+			shown++
+		}
+	}
+
+	alertcard.HiddenNote(builder, len(p.Alerts)-shown+p.TruncatedAlerts)
+	if p.ExternalURL != "" {
+		builder.Note(feishu.NewLarkMd(fmt.Sprintf("接收器 %s · [Alertmanager](%s)", p.Receiver, p.ExternalURL)))
+	}
+
+	return builder.Build()
+}
+
+func (h *Handler) addAlert(builder *feishu.CardBuilder, p *Payload, alert Alert) {
+	// 所有告警共有的注解已经显示在卡片开头
+	var lines []string
+	if summary := firstNonEmpty(alert.Annotations["summary"], alert.Annotations["message"]); summary != "" && summary != p.CommonAnnotations["summary"] {
+		lines = append(lines, "**"+summary+"**")
+	}
+	if description := alert.Annotations["description"]; description != "" && description != p.CommonAnnotations["description"] {
+		lines = append(lines, description)
+	}
+	when := "开始于 " + alert.StartsAt.In(h.location).Format("2006-01-02 15:04:05")
+	if alert.Status == StatusResolved && !alert.EndsAt.IsZero() {
+		when += "，恢复于 " + alert.EndsAt.In(h.location).Format("2006-01-02 15:04:05")
+	}
+	lines = append(lines, when)
+
+	fields := alertcard.LabelFields(alert.Labels, func(name string) bool {
+		_, grouped := p.GroupLabels[name]
+		return grouped
+	})
+	builder.Div(feishu.NewLarkMd(strings.Join(lines, "\n")), fields...)
+
+	var buttons []feishu.CardAction
+	if alert.GeneratorURL != "" {
+		buttons = append(buttons, feishu.NewButton("查看来源", feishu.ButtonDefault).WithURL(alert.GeneratorURL))
+	}
+	if alert.Status != StatusResolved && p.ExternalURL != "" {
+		buttons = append(buttons, feishu.NewButton("静默", feishu.ButtonDanger).WithURL(SilenceURL(p.ExternalURL, alert.Labels)))
+	}
+	if len(buttons) > 0 {
+		builder.Action(buttons...)
+	}
+}
+
+// SilenceURL 返回在 Alertmanager 中新建静默规则的链接，预先填入告警的全部标签
+func SilenceURL(externalURL string, labels map[string]string) string {
+	matchers := make([]string, 0, len(labels))
+	for _, name := range alertcard.SortedKeys(labels) {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	filter := "{" + strings.Join(matchers, ", ") + "}"
+	return strings.TrimRight(externalURL, "/") + "/#/silences/new?filter=" + url.QueryEscape(filter)
+}
+
+// title 与 Alertmanager 默认模板一致，例如 [FIRING:2] HighCPU (api production)
+func title(p *Payload, firing int) string {
+	status := strings.ToUpper(p.Status)
+	if p.Status == StatusFiring {
+		status = fmt.Sprintf("%s:%d", status, firing)
+	}
+	parts := []string{"[" + status + "]"}
+
+	if alertname := p.GroupLabels["alertname"]; alertname != "" {
+		parts = append(parts, alertname)
+	}
+	var values []string
+	for _, name := range alertcard.SortedKeys(p.GroupLabels) {
+		if name != "alertname" {
+			values = append(values, p.GroupLabels[name])
+		}
+	}
+	if len(values) > 0 {
+		parts = append(parts, "("+strings.Join(values, " ")+")")
+	}
+	if len(parts) == 1 {
+		parts = append(parts, fmt.Sprintf("%d 条告警", len(p.Alerts)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
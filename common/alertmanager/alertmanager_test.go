@@ -0,0 +1,214 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+	"github.com/straubel/feishu-webhook/common/feishu/feishutest"
+)
+
+const firingPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighCPU\"}",
+  "truncatedAlerts": 0,
+  "status": "firing",
+  "receiver": "feishu",
+  "groupLabels": {"alertname": "HighCPU", "env": "production"},
+  "commonLabels": {"alertname": "HighCPU", "env": "production", "severity": "critical"},
+  "commonAnnotations": {"summary": "CPU 使用率过高"},
+  "externalURL": "http://alertmanager.example.com",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighCPU", "env": "production", "severity": "critical", "instance": "web-01"},
+      "annotations": {"summary": "CPU 使用率过高", "description": "web-01 CPU 95%"},
+      "startsAt": "2024-05-01T08:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus.example.com/graph?g0.expr=cpu",
+      "fingerprint": "a1"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "HighCPU", "env": "production", "severity": "critical", "instance": "web-02"},
+      "annotations": {"summary": "CPU 使用率过高"},
+      "startsAt": "2024-05-01T07:00:00Z",
+      "endsAt": "2024-05-01T07:30:00Z",
+      "generatorURL": "http://prometheus.example.com/graph?g0.expr=cpu",
+      "fingerprint": "a2"
+    }
+  ]
+}`
+
+type senderFunc func(ctx context.Context, message *feishu.Message) error
+
+func (f senderFunc) SendMessageContext(ctx context.Context, message *feishu.Message) error {
+	return f(ctx, message)
+}
+
+func decodePayload(t *testing.T, data string) *Payload {
+	t.Helper()
+	var p Payload
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("decode payload failed: %v", err)
+	}
+	return &p
+}
+
+func cardJSON(t *testing.T, message *feishu.Message) string {
+	t.Helper()
+	data, err := json.Marshal(message.Content)
+	if err != nil {
+		t.Fatalf("marshal card failed: %v", err)
+	}
+	return string(data)
+}
+
+func TestRender(t *testing.T) {
+	h := NewHandler(nil, WithLocation(time.UTC))
+
+	t.Run("触发中的告警", func(t *testing.T) {
+		message, err := h.Render(decodePayload(t, firingPayload))
+		if err != nil {
+			t.Fatalf("Render() error: %v", err)
+		}
+		if err := message.Validate(); err != nil {
+			t.Fatalf("Validate() error: %v", err)
+		}
+
+		card := message.Content.(*feishu.InteractiveContent)
+		if card.Header.Template != "red" {
+			t.Errorf("header template = %q, want red", card.Header.Template)
+		}
+		if got := card.Header.Title.Content; got != "[FIRING:1] HighCPU (production)" {
+			t.Errorf("title = %q", got)
+		}
+
+		data := cardJSON(t, message)
+		for _, want := range []string{
+			"CPU 使用率过高",
+			"web-01 CPU 95%",
+			`**instance**\nweb-01`,
+			"开始于 2024-05-01 08:00:00",
+			"恢复于 2024-05-01 07:30:00",
+			"触发中（1）",
+			"已恢复（1）",
+			"http://prometheus.example.com/graph?g0.expr=cpu",
+			"/#/silences/new?filter=",
+		} {
+			if !strings.Contains(data, want) {
+				t.Errorf("card should contain %q:\n%s", want, data)
+			}
+		}
+		if strings.Contains(data, `**env**`) {
+			t.Error("group labels should not be repeated as fields")
+		}
+		if strings.Count(data, "静默") != 1 {
+			t.Error("only firing alerts should have a silence button")
+		}
+	})
+
+	t.Run("全部恢复", func(t *testing.T) {
+		p := decodePayload(t, firingPayload)
+		p.Status = StatusResolved
+		p.Alerts = p.Alerts[1:]
+		message, err := h.Render(p)
+		if err != nil {
+			t.Fatalf("Render() error: %v", err)
+		}
+		card := message.Content.(*feishu.InteractiveContent)
+		if card.Header.Template != "green" || card.Header.Title.Content != "[RESOLVED] HighCPU (production)" {
+			t.Errorf("header = %+v, %q", card.Header, card.Header.Title.Content)
+		}
+	})
+
+	t.Run("空分组", func(t *testing.T) {
+		if _, err := NewHandler(nil).Render(&Payload{Version: "4", Status: StatusResolved}); !errors.Is(err, ErrNoAlerts) {
+			t.Errorf("Render() error = %v, want ErrNoAlerts", err)
+		}
+	})
+
+	t.Run("限制告警数量", func(t *testing.T) {
+		p := decodePayload(t, firingPayload)
+		p.TruncatedAlerts = 3
+		message, err := NewHandler(nil, WithMaxAlerts(1)).Render(p)
+		if err != nil {
+			t.Fatalf("Render() error: %v", err)
+		}
+		data := cardJSON(t, message)
+		if strings.Contains(data, "web-02") {
+			t.Error("second alert should be hidden")
+		}
+		if !strings.Contains(data, "还有 4 条告警未显示") {
+			t.Errorf("card should mention hidden alerts:\n%s", data)
+		}
+	})
+}
+
+func TestSilenceURL(t *testing.T) {
+	got := SilenceURL("http://am.example.com/", map[string]string{"b": "2", "a": `x"y`})
+	u, err := url.Parse(strings.Replace(got, "/#/", "/", 1))
+	if err != nil {
+		t.Fatalf("parse %q failed: %v", got, err)
+	}
+	if filter := u.Query().Get("filter"); filter != `{a="x\"y", b="2"}` {
+		t.Errorf("filter = %q", filter)
+	}
+	if !strings.HasPrefix(got, "http://am.example.com/#/silences/new?") {
+		t.Errorf("url = %q", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("通过 Client 发送", func(t *testing.T) {
+		server := feishutest.NewServer(feishutest.WithSecret("secret"))
+		defer server.Close()
+
+		handler := NewHandler(feishu.NewClient(server.URL, "secret"))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(firingPayload)))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+		}
+		messages := server.Messages()
+		if len(messages) != 1 || messages[0].MsgType != feishu.MessageTypeInteractive {
+			t.Errorf("messages = %+v", messages)
+		}
+	})
+
+	ok := senderFunc(func(ctx context.Context, message *feishu.Message) error { return nil })
+	tests := []struct {
+		name   string
+		sender feishu.Sender
+		method string
+		body   string
+		want   int
+	}{
+		{"只接受 POST", ok, http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"无效 JSON", ok, http.MethodPost, "{", http.StatusBadRequest},
+		{"不支持的版本", ok, http.MethodPost, `{"version":"3"}`, http.StatusBadRequest},
+		{"空分组", senderFunc(func(ctx context.Context, message *feishu.Message) error {
+			return errors.New("should not send")
+		}), http.MethodPost, `{"version":"4","status":"resolved","alerts":[]}`, http.StatusNoContent},
+		{"发送失败", senderFunc(func(ctx context.Context, message *feishu.Message) error {
+			return errors.New("down")
+		}), http.MethodPost, firingPayload, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewHandler(tt.sender).ServeHTTP(rec, httptest.NewRequest(tt.method, "/alerts", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package feishu
 
+import "context"
+
 type SDK struct {
 	client *Client
 }
@@ -10,43 +12,121 @@ func New(webhookURL string, secret ...string) *SDK {
 	}
 }
 
+func NewWithOptions(webhookURL string, opts ...ClientOption) *SDK {
+	return &SDK{
+		client: NewClientWithOptions(webhookURL, opts...),
+	}
+}
+
 func (sdk *SDK) SendText(text string) error {
 	return sdk.client.SendText(text)
 }
 
+func (sdk *SDK) SendTextContext(ctx context.Context, text string) error {
+	return sdk.client.SendTextContext(ctx, text)
+}
+
 func (sdk *SDK) SendRichText(title string, content [][]RichTextElement) error {
 	return sdk.client.SendRichText(title, content)
 }
 
+func (sdk *SDK) SendRichTextContext(ctx context.Context, title string, content [][]RichTextElement) error {
+	return sdk.client.SendRichTextContext(ctx, title, content)
+}
+
+func (sdk *SDK) SendLocalizedRichText(builder *PostBuilder) error {
+	return sdk.client.SendLocalizedRichText(builder)
+}
+
+func (sdk *SDK) SendLocalizedRichTextContext(ctx context.Context, builder *PostBuilder) error {
+	return sdk.client.SendLocalizedRichTextContext(ctx, builder)
+}
+
+func (sdk *SDK) SendMarkdownAsPost(title, markdown string) error {
+	return sdk.client.SendMarkdownAsPost(title, markdown)
+}
+
+func (sdk *SDK) SendMarkdownAsPostContext(ctx context.Context, title, markdown string) error {
+	return sdk.client.SendMarkdownAsPostContext(ctx, title, markdown)
+}
+
 func (sdk *SDK) SendImage(imageKey string) error {
 	return sdk.client.SendImage(imageKey)
 }
 
+func (sdk *SDK) SendImageContext(ctx context.Context, imageKey string) error {
+	return sdk.client.SendImageContext(ctx, imageKey)
+}
+
 func (sdk *SDK) SendInteractive(config *CardConfig, header *CardHeader, elements []interface{}) error {
 	return sdk.client.SendInteractive(config, header, elements)
 }
 
+func (sdk *SDK) SendInteractiveContext(ctx context.Context, config *CardConfig, header *CardHeader, elements []interface{}) error {
+	return sdk.client.SendInteractiveContext(ctx, config, header, elements)
+}
+
+func (sdk *SDK) SendCard(builder *CardBuilder) error {
+	return sdk.client.SendCard(builder)
+}
+
+func (sdk *SDK) SendCardContext(ctx context.Context, builder *CardBuilder) error {
+	return sdk.client.SendCardContext(ctx, builder)
+}
+
+func (sdk *SDK) SendCardV2(card *CardV2) error {
+	return sdk.client.SendCardV2(card)
+}
+
+func (sdk *SDK) SendCardV2Context(ctx context.Context, card *CardV2) error {
+	return sdk.client.SendCardV2Context(ctx, card)
+}
+
+func (sdk *SDK) SendCardTemplate(templateID, version string, vars TemplateVariables) error {
+	return sdk.client.SendCardTemplate(templateID, version, vars)
+}
+
+func (sdk *SDK) SendCardTemplateContext(ctx context.Context, templateID, version string, vars TemplateVariables) error {
+	return sdk.client.SendCardTemplateContext(ctx, templateID, version, vars)
+}
+
 func (sdk *SDK) SendMessage(message *Message) error {
 	return sdk.client.SendMessage(message)
 }
 
+func (sdk *SDK) SendMessageContext(ctx context.Context, message *Message) error {
+	return sdk.client.SendMessageContext(ctx, message)
+}
+
 func (sdk *SDK) Client() *Client {
 	return sdk.client
 }
 
 func SendTextMessage(webhookURL, text string, secret ...string) error {
+	return SendTextMessageContext(context.Background(), webhookURL, text, secret...)
+}
+
+func SendTextMessageContext(ctx context.Context, webhookURL, text string, secret ...string) error {
 	client := NewClient(webhookURL, secret...)
-	return client.SendText(text)
+	return client.SendTextContext(ctx, text)
 }
 
 func SendRichTextMessage(webhookURL, title string, content [][]RichTextElement, secret ...string) error {
+	return SendRichTextMessageContext(context.Background(), webhookURL, title, content, secret...)
+}
+
+func SendRichTextMessageContext(ctx context.Context, webhookURL, title string, content [][]RichTextElement, secret ...string) error {
 	client := NewClient(webhookURL, secret...)
-	return client.SendRichText(title, content)
+	return client.SendRichTextContext(ctx, title, content)
 }
 
 func SendImageMessage(webhookURL, imageKey string, secret ...string) error {
+	return SendImageMessageContext(context.Background(), webhookURL, imageKey, secret...)
+}
+
+func SendImageMessageContext(ctx context.Context, webhookURL, imageKey string, secret ...string) error {
 	client := NewClient(webhookURL, secret...)
-	return client.SendImage(imageKey)
+	return client.SendImageContext(ctx, imageKey)
 }
 
 func CreateRichTextElement(tag, text string, options ...map[string]string) RichTextElement {
@@ -69,6 +149,15 @@ func CreateRichTextElement(tag, text string, options ...map[string]string) RichT
 		if imageKey, ok := opts["image_key"]; ok {
 			element.ImageKey = imageKey
 		}
+		if fileKey, ok := opts["file_key"]; ok {
+			element.FileKey = fileKey
+		}
+		if emojiType, ok := opts["emoji_type"]; ok {
+			element.EmojiType = emojiType
+		}
+		if language, ok := opts["language"]; ok {
+			element.Language = language
+		}
 	}
 	
 	return element
@@ -1,6 +1,8 @@
 package feishu
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -351,3 +353,73 @@ func TestCreateCardConfigExtended(t *testing.T) {
 		})
 	}
 }
+
+func TestSDKContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code": 0, "msg": "success"}`))
+	}))
+	defer server.Close()
+
+	t.Run("SDK带上下文发送", func(t *testing.T) {
+		sdk := New(server.URL, "test-secret")
+		ctx := context.Background()
+		content := [][]RichTextElement{
+			{CreateRichTextElement("text", "Test content")},
+		}
+
+		if err := sdk.SendTextContext(ctx, "Test message"); err != nil {
+			t.Errorf("SendTextContext error: %v", err)
+		}
+		if err := sdk.SendRichTextContext(ctx, "Title", content); err != nil {
+			t.Errorf("SendRichTextContext error: %v", err)
+		}
+		if err := sdk.SendImageContext(ctx, "test_image_key"); err != nil {
+			t.Errorf("SendImageContext error: %v", err)
+		}
+		if err := sdk.SendInteractiveContext(ctx, CreateCardConfig(true), CreateCardHeader("Card", "blue"), []interface{}{NewHr()}); err != nil {
+			t.Errorf("SendInteractiveContext error: %v", err)
+		}
+		if err := sdk.SendMessageContext(ctx, NewTextMessage("Test")); err != nil {
+			t.Errorf("SendMessageContext error: %v", err)
+		}
+	})
+
+	t.Run("便捷函数响应取消", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := SendTextMessageContext(ctx, server.URL, "Test", "secret"); !errors.Is(err, context.Canceled) {
+			t.Errorf("SendTextMessageContext should be canceled, got: %v", err)
+		}
+		content := [][]RichTextElement{{CreateRichTextElement("text", "Test")}}
+		if err := SendRichTextMessageContext(ctx, server.URL, "Title", content); !errors.Is(err, context.Canceled) {
+			t.Errorf("SendRichTextMessageContext should be canceled, got: %v", err)
+		}
+		if err := SendImageMessageContext(ctx, server.URL, "image_key"); !errors.Is(err, context.Canceled) {
+			t.Errorf("SendImageMessageContext should be canceled, got: %v", err)
+		}
+	})
+}
+
+func TestSDKSendCardTemplate(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0, "msg": "success"}`))
+	}))
+	defer server.Close()
+
+	sdk := New(server.URL, "test-secret")
+	if err := sdk.SendCardTemplate("AAqk1234", "", TemplateVariables{"title": "test"}); err != nil {
+		t.Errorf("SendCardTemplate() error: %v", err)
+	}
+	if err := sdk.SendCardTemplateContext(context.Background(), "", "", nil); !errors.Is(err, ErrEmptyTemplateID) {
+		t.Errorf("SendCardTemplateContext() error = %v, want ErrEmptyTemplateID", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
@@ -0,0 +1,237 @@
+package feishu
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrQueueFull      = errors.New("feishu: async queue full")
+	ErrMessageDropped = errors.New("feishu: message dropped from async queue")
+	ErrSenderClosed   = errors.New("feishu: async sender closed")
+)
+
+// Sender 是发送消息的最小接口，Client 实现了该接口
+type Sender interface {
+	SendMessageContext(ctx context.Context, message *Message) error
+}
+
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 队列满时阻塞，直到有空位或 ctx 结束
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest 丢弃最早入队的消息
+	OverflowDropOldest
+	// OverflowDropNewest 丢弃当前消息并返回 ErrQueueFull
+	OverflowDropNewest
+)
+
+// Callback 在消息发送完成或被丢弃时调用
+type Callback func(message *Message, err error)
+
+type AsyncOption func(*AsyncSender)
+
+func WithQueueSize(size int) AsyncOption {
+	return func(s *AsyncSender) {
+		if size > 0 {
+			s.size = size
+		}
+	}
+}
+
+func WithWorkers(workers int) AsyncOption {
+	return func(s *AsyncSender) {
+		if workers > 0 {
+			s.workers = workers
+		}
+	}
+}
+
+func WithOverflowPolicy(policy OverflowPolicy) AsyncOption {
+	return func(s *AsyncSender) {
+		s.policy = policy
+	}
+}
+
+type asyncItem struct {
+	message  *Message
+	callback Callback
+}
+
+// AsyncSender 使用有界队列和工作协程异步发送消息
+type AsyncSender struct {
+	sender  Sender
+	size    int
+	workers int
+	policy  OverflowPolicy
+
+	mu      sync.Mutex
+	queue   []*asyncItem
+	pending int
+	closed  bool
+	changed chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewAsyncSender(sender Sender, opts ...AsyncOption) *AsyncSender {
+	s := &AsyncSender{
+		sender:  sender,
+		size:    100,
+		workers: 1,
+		changed: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	return s
+}
+
+// Send 将消息放入队列，ctx 只控制 OverflowBlock 模式下的等待
+func (s *AsyncSender) Send(ctx context.Context, message *Message, callback Callback) error {
+	item := &asyncItem{message: message, callback: callback}
+
+	s.mu.Lock()
+	for {
+		if s.closed {
+			s.mu.Unlock()
+			return ErrSenderClosed
+		}
+		if len(s.queue) < s.size {
+			break
+		}
+
+		switch s.policy {
+		case OverflowDropNewest:
+			s.mu.Unlock()
+			return ErrQueueFull
+		case OverflowDropOldest:
+			dropped := s.queue[0]
+			s.queue = s.queue[1:]
+			s.pending--
+			s.mu.Unlock()
+			dropped.done(ErrMessageDropped)
+			s.mu.Lock()
+			continue
+		}
+
+		if err := s.wait(ctx); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	}
+
+	s.queue = append(s.queue, item)
+	s.pending++
+	s.broadcast()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *AsyncSender) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.queue)
+}
+
+// Flush 等待当前队列中的消息和正在发送的消息全部完成
+func (s *AsyncSender) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.pending > 0 {
+		if err := s.wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 停止接收新消息并发送完剩余消息；ctx 结束时中断仍在进行的发送
+func (s *AsyncSender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		s.broadcast()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *AsyncSender) work() {
+	defer s.wg.Done()
+
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.wait(context.Background())
+		}
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		item := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.broadcast()
+		s.mu.Unlock()
+
+		item.done(s.sender.SendMessageContext(s.ctx, item.message))
+
+		s.mu.Lock()
+		s.pending--
+		s.broadcast()
+		s.mu.Unlock()
+	}
+}
+
+// wait 在持有锁时调用，释放锁直到状态变化或 ctx 结束
+func (s *AsyncSender) wait(ctx context.Context) error {
+	changed := s.changed
+	s.mu.Unlock()
+	defer s.mu.Lock()
+
+	select {
+	case <-changed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *AsyncSender) broadcast() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (item *asyncItem) done(err error) {
+	if item.callback != nil {
+		item.callback(item.message, err)
+	}
+}
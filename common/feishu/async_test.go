@@ -0,0 +1,219 @@
+package feishu
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type senderFunc func(ctx context.Context, message *Message) error
+
+func (f senderFunc) SendMessageContext(ctx context.Context, message *Message) error {
+	return f(ctx, message)
+}
+
+func textOf(message *Message) string {
+	if content, ok := message.Content.(*TextContent); ok {
+		return content.Text
+	}
+	return ""
+}
+
+func TestAsyncSender(t *testing.T) {
+	t.Run("通过Client异步发送", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			successHandler(w, r)
+		}))
+		defer server.Close()
+
+		sender := NewAsyncSender(NewClient(server.URL), WithWorkers(4))
+
+		var mu sync.Mutex
+		var results []error
+		for i := 0; i < 10; i++ {
+			err := sender.Send(context.Background(), NewTextMessage("async"), func(message *Message, err error) {
+				mu.Lock()
+				results = append(results, err)
+				mu.Unlock()
+			})
+			if err != nil {
+				t.Fatalf("Send() error: %v", err)
+			}
+		}
+
+		if err := sender.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush() error: %v", err)
+		}
+		if got := atomic.LoadInt32(&calls); got != 10 {
+			t.Errorf("server calls = %d, want 10", got)
+		}
+		mu.Lock()
+		if len(results) != 10 {
+			t.Errorf("callbacks = %d, want 10", len(results))
+		}
+		for _, err := range results {
+			if err != nil {
+				t.Errorf("callback error: %v", err)
+			}
+		}
+		mu.Unlock()
+
+		if err := sender.Close(context.Background()); err != nil {
+			t.Errorf("Close() error: %v", err)
+		}
+		if err := sender.Send(context.Background(), NewTextMessage("late"), nil); !errors.Is(err, ErrSenderClosed) {
+			t.Errorf("Send() after Close = %v, want ErrSenderClosed", err)
+		}
+	})
+
+	t.Run("回调收到发送错误", func(t *testing.T) {
+		wantErr := errors.New("boom")
+		sender := NewAsyncSender(senderFunc(func(ctx context.Context, message *Message) error {
+			return wantErr
+		}))
+		defer sender.Close(context.Background())
+
+		got := make(chan error, 1)
+		sender.Send(context.Background(), NewTextMessage("x"), func(message *Message, err error) {
+			got <- err
+		})
+		if err := <-got; !errors.Is(err, wantErr) {
+			t.Errorf("callback error = %v, want %v", err, wantErr)
+		}
+	})
+}
+
+func TestAsyncSenderOverflow(t *testing.T) {
+	newBlockedSender := func(policy OverflowPolicy) (*AsyncSender, chan struct{}, *[]string, *sync.Mutex) {
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+		var mu sync.Mutex
+		var sent []string
+		sender := NewAsyncSender(senderFunc(func(ctx context.Context, message *Message) error {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-release
+			mu.Lock()
+			sent = append(sent, textOf(message))
+			mu.Unlock()
+			return nil
+		}), WithQueueSize(2), WithOverflowPolicy(policy))
+
+		sender.Send(context.Background(), NewTextMessage("in-flight"), nil)
+		<-started
+		return sender, release, &sent, &mu
+	}
+
+	t.Run("丢弃最新", func(t *testing.T) {
+		sender, release, sent, mu := newBlockedSender(OverflowDropNewest)
+
+		sender.Send(context.Background(), NewTextMessage("a"), nil)
+		sender.Send(context.Background(), NewTextMessage("b"), nil)
+		if err := sender.Send(context.Background(), NewTextMessage("c"), nil); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Send() = %v, want ErrQueueFull", err)
+		}
+
+		close(release)
+		sender.Close(context.Background())
+		mu.Lock()
+		defer mu.Unlock()
+		if len(*sent) != 3 || (*sent)[1] != "a" || (*sent)[2] != "b" {
+			t.Errorf("sent = %v", *sent)
+		}
+	})
+
+	t.Run("丢弃最旧", func(t *testing.T) {
+		sender, release, sent, mu := newBlockedSender(OverflowDropOldest)
+
+		dropped := make(chan error, 1)
+		sender.Send(context.Background(), NewTextMessage("a"), func(message *Message, err error) {
+			dropped <- err
+		})
+		sender.Send(context.Background(), NewTextMessage("b"), nil)
+		if err := sender.Send(context.Background(), NewTextMessage("c"), nil); err != nil {
+			t.Errorf("Send() error: %v", err)
+		}
+		if err := <-dropped; !errors.Is(err, ErrMessageDropped) {
+			t.Errorf("dropped callback = %v, want ErrMessageDropped", err)
+		}
+
+		close(release)
+		sender.Close(context.Background())
+		mu.Lock()
+		defer mu.Unlock()
+		if len(*sent) != 3 || (*sent)[1] != "b" || (*sent)[2] != "c" {
+			t.Errorf("sent = %v", *sent)
+		}
+	})
+
+	t.Run("阻塞直到超时", func(t *testing.T) {
+		sender, release, _, _ := newBlockedSender(OverflowBlock)
+
+		sender.Send(context.Background(), NewTextMessage("a"), nil)
+		sender.Send(context.Background(), NewTextMessage("b"), nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := sender.Send(ctx, NewTextMessage("c"), nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Send() = %v, want DeadlineExceeded", err)
+		}
+
+		close(release)
+		sender.Close(context.Background())
+	})
+}
+
+func TestAsyncSenderClose(t *testing.T) {
+	t.Run("关闭时发送剩余消息", func(t *testing.T) {
+		var sent int32
+		sender := NewAsyncSender(senderFunc(func(ctx context.Context, message *Message) error {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&sent, 1)
+			return nil
+		}), WithQueueSize(20))
+
+		for i := 0; i < 20; i++ {
+			sender.Send(context.Background(), NewTextMessage("x"), nil)
+		}
+		if err := sender.Close(context.Background()); err != nil {
+			t.Fatalf("Close() error: %v", err)
+		}
+		if got := atomic.LoadInt32(&sent); got != 20 {
+			t.Errorf("sent = %d, want 20", got)
+		}
+	})
+
+	t.Run("超时中断发送", func(t *testing.T) {
+		sender := NewAsyncSender(senderFunc(func(ctx context.Context, message *Message) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			sender.Send(context.Background(), NewTextMessage("x"), func(message *Message, err error) {
+				errs <- err
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := sender.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Close() = %v, want DeadlineExceeded", err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := <-errs; !errors.Is(err, context.Canceled) {
+				t.Errorf("callback error = %v, want Canceled", err)
+			}
+		}
+	})
+}
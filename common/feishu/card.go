@@ -0,0 +1,520 @@
+package feishu
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	TextTagPlain  = "plain_text"
+	TextTagLarkMd = "lark_md"
+
+	ButtonDefault = "default"
+	ButtonPrimary = "primary"
+	ButtonDanger  = "danger"
+)
+
+// CardElement 是交互式卡片中可以放入 elements 的组件
+type CardElement interface {
+	Validate() error
+	validate(v *validator, path string)
+}
+
+// CardAction 是可以放入 action 组件中的交互组件
+type CardAction interface {
+	CardElement
+	isCardAction()
+}
+
+type CardText struct {
+	Tag     string `json:"tag"`
+	Content string `json:"content"`
+	Lines   int    `json:"lines,omitempty"`
+}
+
+type CardField struct {
+	IsShort bool      `json:"is_short"`
+	Text    *CardText `json:"text"`
+}
+
+type DivElement struct {
+	Tag    string       `json:"tag"`
+	Text   *CardText    `json:"text,omitempty"`
+	Fields []*CardField `json:"fields,omitempty"`
+	Extra  CardElement  `json:"extra,omitempty"`
+}
+
+type MarkdownElement struct {
+	Tag       string `json:"tag"`
+	Content   string `json:"content"`
+	TextAlign string `json:"text_align,omitempty"`
+}
+
+type HrElement struct {
+	Tag string `json:"tag"`
+}
+
+type ImgElement struct {
+	Tag     string    `json:"tag"`
+	ImgKey  string    `json:"img_key"`
+	Alt     *CardText `json:"alt"`
+	Title   *CardText `json:"title,omitempty"`
+	Mode    string    `json:"mode,omitempty"`
+	Preview *bool     `json:"preview,omitempty"`
+}
+
+type NoteElement struct {
+	Tag      string        `json:"tag"`
+	Elements []CardElement `json:"elements"`
+}
+
+type ActionElement struct {
+	Tag     string       `json:"tag"`
+	Actions []CardAction `json:"actions"`
+	Layout  string       `json:"layout,omitempty"`
+}
+
+type ButtonAction struct {
+	Tag            string                 `json:"tag"`
+	Text           *CardText              `json:"text"`
+	URL            string                 `json:"url,omitempty"`
+	Type           string                 `json:"type,omitempty"`
+	Value          map[string]interface{} `json:"value,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	FormActionType string                 `json:"form_action_type,omitempty"`
+}
+
+type SelectOption struct {
+	Text  *CardText `json:"text"`
+	Value string    `json:"value"`
+	URL   string    `json:"url,omitempty"`
+}
+
+type SelectStaticAction struct {
+	Tag           string                 `json:"tag"`
+	Placeholder   *CardText              `json:"placeholder,omitempty"`
+	InitialOption string                 `json:"initial_option,omitempty"`
+	Options       []*SelectOption        `json:"options"`
+	Value         map[string]interface{} `json:"value,omitempty"`
+}
+
+type OverflowAction struct {
+	Tag     string                 `json:"tag"`
+	Options []*SelectOption        `json:"options"`
+	Value   map[string]interface{} `json:"value,omitempty"`
+}
+
+type DatePickerAction struct {
+	Tag         string                 `json:"tag"`
+	Placeholder *CardText              `json:"placeholder,omitempty"`
+	InitialDate string                 `json:"initial_date,omitempty"`
+	Value       map[string]interface{} `json:"value,omitempty"`
+}
+
+type ColumnSetElement struct {
+	Tag             string    `json:"tag"`
+	FlexMode        string    `json:"flex_mode,omitempty"`
+	BackgroundStyle string    `json:"background_style,omitempty"`
+	Columns         []*Column `json:"columns"`
+}
+
+type Column struct {
+	Tag           string        `json:"tag"`
+	Width         string        `json:"width,omitempty"`
+	Weight        int           `json:"weight,omitempty"`
+	VerticalAlign string        `json:"vertical_align,omitempty"`
+	Elements      []CardElement `json:"elements"`
+}
+
+func NewPlainText(content string) *CardText {
+	return &CardText{Tag: TextTagPlain, Content: content}
+}
+
+func NewLarkMd(content string) *CardText {
+	return &CardText{Tag: TextTagLarkMd, Content: content}
+}
+
+func NewField(isShort bool, text *CardText) *CardField {
+	return &CardField{IsShort: isShort, Text: text}
+}
+
+func NewDiv(text *CardText, fields ...*CardField) *DivElement {
+	return &DivElement{Tag: "div", Text: text, Fields: fields}
+}
+
+func NewMarkdown(content string) *MarkdownElement {
+	return &MarkdownElement{Tag: "markdown", Content: content}
+}
+
+func NewHr() *HrElement {
+	return &HrElement{Tag: "hr"}
+}
+
+func NewImg(imgKey, alt string) *ImgElement {
+	return &ImgElement{Tag: "img", ImgKey: imgKey, Alt: NewPlainText(alt)}
+}
+
+func NewNote(elements ...CardElement) *NoteElement {
+	return &NoteElement{Tag: "note", Elements: elements}
+}
+
+func NewAction(actions ...CardAction) *ActionElement {
+	return &ActionElement{Tag: "action", Actions: actions}
+}
+
+func NewButton(text, buttonType string) *ButtonAction {
+	return &ButtonAction{Tag: "button", Text: NewPlainText(text), Type: buttonType}
+}
+
+func NewSelectOption(text, value string) *SelectOption {
+	return &SelectOption{Text: NewPlainText(text), Value: value}
+}
+
+func NewSelectStatic(placeholder string, options ...*SelectOption) *SelectStaticAction {
+	return &SelectStaticAction{Tag: "select_static", Placeholder: NewPlainText(placeholder), Options: options}
+}
+
+func NewOverflow(options ...*SelectOption) *OverflowAction {
+	return &OverflowAction{Tag: "overflow", Options: options}
+}
+
+func NewDatePicker(placeholder string) *DatePickerAction {
+	return &DatePickerAction{Tag: "date_picker", Placeholder: NewPlainText(placeholder)}
+}
+
+func NewColumnSet(columns ...*Column) *ColumnSetElement {
+	return &ColumnSetElement{Tag: "column_set", FlexMode: "none", Columns: columns}
+}
+
+func NewColumn(elements ...CardElement) *Column {
+	return &Column{Tag: "column", Width: "weighted", Weight: 1, Elements: elements}
+}
+
+func (b *ButtonAction) WithURL(url string) *ButtonAction {
+	b.URL = url
+	return b
+}
+
+func (b *ButtonAction) WithValue(value map[string]interface{}) *ButtonAction {
+	b.Value = value
+	return b
+}
+
+func (d *DivElement) WithExtra(extra CardElement) *DivElement {
+	d.Extra = extra
+	return d
+}
+
+func (a *ActionElement) WithLayout(layout string) *ActionElement {
+	a.Layout = layout
+	return a
+}
+
+func (c *Column) WithWeight(weight int) *Column {
+	c.Weight = weight
+	return c
+}
+
+func (d *DatePickerAction) WithInitialDate(date string) *DatePickerAction {
+	d.InitialDate = date
+	return d
+}
+
+func (*ButtonAction) isCardAction()       {}
+func (*SelectStaticAction) isCardAction() {}
+func (*OverflowAction) isCardAction()     {}
+func (*DatePickerAction) isCardAction()   {}
+
+func (t *CardText) Validate() error           { return validateCardElement(t) }
+func (d *DivElement) Validate() error         { return validateCardElement(d) }
+func (m *MarkdownElement) Validate() error    { return validateCardElement(m) }
+func (h *HrElement) Validate() error          { return validateCardElement(h) }
+func (i *ImgElement) Validate() error         { return validateCardElement(i) }
+func (n *NoteElement) Validate() error        { return validateCardElement(n) }
+func (a *ActionElement) Validate() error      { return validateCardElement(a) }
+func (b *ButtonAction) Validate() error       { return validateCardElement(b) }
+func (s *SelectStaticAction) Validate() error { return validateCardElement(s) }
+func (o *OverflowAction) Validate() error     { return validateCardElement(o) }
+func (d *DatePickerAction) Validate() error   { return validateCardElement(d) }
+func (c *ColumnSetElement) Validate() error   { return validateCardElement(c) }
+
+func validateCardElement(e CardElement) error {
+	v := &validator{}
+	validateElement(v, "", e)
+	return v.err()
+}
+
+// validateElement 把接口中的 nil 指针（例如 Add((*DivElement)(nil))）也当作 nil 处理，避免校验时 panic
+func validateElement(v *validator, path string, e CardElement) {
+	if e == nil {
+		v.add(path, "is nil")
+		return
+	}
+	if rv := reflect.ValueOf(e); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		v.add(path, "is nil")
+		return
+	}
+	e.validate(v, path)
+}
+
+// validateBodyElement 校验放在 elements 中的组件，文本只能作为 note 的子元素
+func validateBodyElement(v *validator, path string, e CardElement) {
+	if t, ok := e.(*CardText); ok && t != nil {
+		v.add(path, "%s is only allowed inside note", t.Tag)
+		return
+	}
+	validateElement(v, path, e)
+}
+
+func validateTag(v *validator, path, got, want string) {
+	if got != want {
+		v.add(joinPath(path, "tag"), "must be %q, got %q", want, got)
+	}
+}
+
+func validateCardText(v *validator, path string, t *CardText) {
+	if t == nil {
+		v.add(path, "is required")
+		return
+	}
+	t.validate(v, path)
+}
+
+func (t *CardText) validate(v *validator, path string) {
+	if t.Tag != TextTagPlain && t.Tag != TextTagLarkMd {
+		v.add(joinPath(path, "tag"), "must be %q or %q, got %q", TextTagPlain, TextTagLarkMd, t.Tag)
+	}
+	if t.Content == "" {
+		v.add(joinPath(path, "content"), "is empty")
+	}
+}
+
+func (d *DivElement) validate(v *validator, path string) {
+	validateTag(v, path, d.Tag, "div")
+	if d.Text == nil && len(d.Fields) == 0 {
+		v.add(path, "text or fields is required")
+	}
+	if d.Text != nil {
+		d.Text.validate(v, joinPath(path, "text"))
+	}
+	for i, field := range d.Fields {
+		if field == nil {
+			v.add(indexPath(path, "fields", i), "is nil")
+			continue
+		}
+		validateCardText(v, joinPath(indexPath(path, "fields", i), "text"), field.Text)
+	}
+	if d.Extra != nil {
+		validateElement(v, joinPath(path, "extra"), d.Extra)
+	}
+}
+
+func (m *MarkdownElement) validate(v *validator, path string) {
+	validateTag(v, path, m.Tag, "markdown")
+	if m.Content == "" {
+		v.add(joinPath(path, "content"), "is empty")
+	}
+}
+
+func (h *HrElement) validate(v *validator, path string) {
+	validateTag(v, path, h.Tag, "hr")
+}
+
+func (i *ImgElement) validate(v *validator, path string) {
+	validateTag(v, path, i.Tag, "img")
+	if i.ImgKey == "" {
+		v.add(joinPath(path, "img_key"), "is empty")
+	}
+	if i.Alt == nil {
+		v.add(joinPath(path, "alt"), "is required")
+	}
+	if i.Title != nil {
+		i.Title.validate(v, joinPath(path, "title"))
+	}
+}
+
+func (n *NoteElement) validate(v *validator, path string) {
+	validateTag(v, path, n.Tag, "note")
+	if len(n.Elements) == 0 {
+		v.add(joinPath(path, "elements"), "is empty")
+	}
+	for i, element := range n.Elements {
+		elementPath := indexPath(path, "elements", i)
+		switch element.(type) {
+		case *CardText, *ImgElement:
+			validateElement(v, elementPath, element)
+		default:
+			v.add(elementPath, "note only accepts text and img, got %T", element)
+		}
+	}
+}
+
+func (a *ActionElement) validate(v *validator, path string) {
+	validateTag(v, path, a.Tag, "action")
+	if len(a.Actions) == 0 {
+		v.add(joinPath(path, "actions"), "is empty")
+	}
+	for i, action := range a.Actions {
+		validateElement(v, indexPath(path, "actions", i), action)
+	}
+}
+
+func (b *ButtonAction) validate(v *validator, path string) {
+	validateTag(v, path, b.Tag, "button")
+	validateCardText(v, joinPath(path, "text"), b.Text)
+	switch b.Type {
+	case "", ButtonDefault, ButtonPrimary, ButtonDanger:
+	default:
+		v.add(joinPath(path, "type"), "unknown button type %q", b.Type)
+	}
+}
+
+func validateOptions(v *validator, path string, options []*SelectOption) {
+	if len(options) == 0 {
+		v.add(joinPath(path, "options"), "is empty")
+	}
+	for i, option := range options {
+		optionPath := indexPath(path, "options", i)
+		if option == nil {
+			v.add(optionPath, "is nil")
+			continue
+		}
+		validateCardText(v, joinPath(optionPath, "text"), option.Text)
+		if option.Value == "" && option.URL == "" {
+			v.add(joinPath(optionPath, "value"), "is empty")
+		}
+	}
+}
+
+func (s *SelectStaticAction) validate(v *validator, path string) {
+	validateTag(v, path, s.Tag, "select_static")
+	validateOptions(v, path, s.Options)
+	if s.InitialOption != "" {
+		found := false
+		for _, option := range s.Options {
+			if option != nil && option.Value == s.InitialOption {
+				found = true
+			}
+		}
+		if !found {
+			v.add(joinPath(path, "initial_option"), "%q is not one of the options", s.InitialOption)
+		}
+	}
+}
+
+func (o *OverflowAction) validate(v *validator, path string) {
+	validateTag(v, path, o.Tag, "overflow")
+	validateOptions(v, path, o.Options)
+}
+
+func (d *DatePickerAction) validate(v *validator, path string) {
+	validateTag(v, path, d.Tag, "date_picker")
+	if d.InitialDate != "" {
+		if _, err := time.Parse("2006-1-2", d.InitialDate); err != nil {
+			v.add(joinPath(path, "initial_date"), "must be formatted as yyyy-MM-dd, got %q", d.InitialDate)
+		}
+	}
+}
+
+func (c *ColumnSetElement) validate(v *validator, path string) {
+	validateTag(v, path, c.Tag, "column_set")
+	if len(c.Columns) == 0 {
+		v.add(joinPath(path, "columns"), "is empty")
+	}
+	for i, column := range c.Columns {
+		columnPath := indexPath(path, "columns", i)
+		if column == nil {
+			v.add(columnPath, "is nil")
+			continue
+		}
+		validateTag(v, columnPath, column.Tag, "column")
+		for j, element := range column.Elements {
+			validateBodyElement(v, indexPath(columnPath, "elements", j), element)
+		}
+	}
+}
+
+// CardBuilder 用于链式构建交互式卡片，Build 时统一校验
+type CardBuilder struct {
+	config   *CardConfig
+	header   *CardHeader
+	elements []CardElement
+}
+
+func NewCardBuilder() *CardBuilder {
+	return &CardBuilder{}
+}
+
+func (b *CardBuilder) Config(config *CardConfig) *CardBuilder {
+	b.config = config
+	return b
+}
+
+func (b *CardBuilder) Header(title, template string) *CardBuilder {
+	b.header = CreateCardHeader(title, template)
+	return b
+}
+
+func (b *CardBuilder) Add(elements ...CardElement) *CardBuilder {
+	b.elements = append(b.elements, elements...)
+	return b
+}
+
+func (b *CardBuilder) Div(text *CardText, fields ...*CardField) *CardBuilder {
+	return b.Add(NewDiv(text, fields...))
+}
+
+func (b *CardBuilder) Markdown(content string) *CardBuilder {
+	return b.Add(NewMarkdown(content))
+}
+
+func (b *CardBuilder) Hr() *CardBuilder {
+	return b.Add(NewHr())
+}
+
+func (b *CardBuilder) Img(imgKey, alt string) *CardBuilder {
+	return b.Add(NewImg(imgKey, alt))
+}
+
+func (b *CardBuilder) Note(elements ...CardElement) *CardBuilder {
+	return b.Add(NewNote(elements...))
+}
+
+func (b *CardBuilder) Action(actions ...CardAction) *CardBuilder {
+	return b.Add(NewAction(actions...))
+}
+
+func (b *CardBuilder) ColumnSet(columns ...*Column) *CardBuilder {
+	return b.Add(NewColumnSet(columns...))
+}
+
+// Elements 校验并返回可传给 SendInteractive 的元素列表
+func (b *CardBuilder) Elements() ([]interface{}, error) {
+	v := &validator{}
+	if len(b.elements) == 0 {
+		v.add("elements", "is empty")
+	}
+	if b.header != nil && (b.header.Title == nil || b.header.Title.Content == "") {
+		v.add("header.title.content", "is empty")
+	}
+
+	elements := make([]interface{}, len(b.elements))
+	for i, element := range b.elements {
+		validateBodyElement(v, fmt.Sprintf("elements[%d]", i), element)
+		elements[i] = element
+	}
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+func (b *CardBuilder) Build() (*Message, error) {
+	elements, err := b.Elements()
+	if err != nil {
+		return nil, err
+	}
+	return NewInteractiveMessage(b.config, b.header, elements), nil
+}
@@ -0,0 +1,228 @@
+package feishu
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func assertJSON(t *testing.T, v interface{}, want string) {
+	t.Helper()
+
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var gotValue, wantValue interface{}
+	json.Unmarshal(got, &gotValue)
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expected JSON: %v", err)
+	}
+
+	gotJSON, _ := json.Marshal(gotValue)
+	wantJSON, _ := json.Marshal(wantValue)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("JSON = %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+func TestCardElementSerialization(t *testing.T) {
+	tests := []struct {
+		name    string
+		element CardElement
+		want    string
+	}{
+		{
+			name:    "div文本与字段",
+			element: NewDiv(NewLarkMd("**状态**"), NewField(true, NewLarkMd("**环境:**\nproduction"))),
+			want:    `{"tag":"div","text":{"tag":"lark_md","content":"**状态**"},"fields":[{"is_short":true,"text":{"tag":"lark_md","content":"**环境:**\nproduction"}}]}`,
+		},
+		{
+			name:    "markdown",
+			element: NewMarkdown("**hello**"),
+			want:    `{"tag":"markdown","content":"**hello**"}`,
+		},
+		{
+			name:    "分割线",
+			element: NewHr(),
+			want:    `{"tag":"hr"}`,
+		},
+		{
+			name:    "图片",
+			element: NewImg("img_v2_xxx", "示意图"),
+			want:    `{"tag":"img","img_key":"img_v2_xxx","alt":{"tag":"plain_text","content":"示意图"}}`,
+		},
+		{
+			name:    "备注",
+			element: NewNote(NewPlainText("来自监控系统")),
+			want:    `{"tag":"note","elements":[{"tag":"plain_text","content":"来自监控系统"}]}`,
+		},
+		{
+			name: "按钮",
+			element: NewAction(
+				NewButton("查看详情", ButtonPrimary).WithURL("https://example.com").WithValue(map[string]interface{}{"key": "v"}),
+			).WithLayout("bisected"),
+			want: `{"tag":"action","layout":"bisected","actions":[{"tag":"button","text":{"tag":"plain_text","content":"查看详情"},"url":"https://example.com","type":"primary","value":{"key":"v"}}]}`,
+		},
+		{
+			name: "下拉选择与折叠菜单",
+			element: NewAction(
+				NewSelectStatic("请选择", NewSelectOption("选项1", "1")),
+				NewOverflow(NewSelectOption("更多", "more")),
+				NewDatePicker("日期").WithInitialDate("2024-01-02"),
+			),
+			want: `{"tag":"action","actions":[` +
+				`{"tag":"select_static","placeholder":{"tag":"plain_text","content":"请选择"},"options":[{"text":{"tag":"plain_text","content":"选项1"},"value":"1"}]},` +
+				`{"tag":"overflow","options":[{"text":{"tag":"plain_text","content":"更多"},"value":"more"}]},` +
+				`{"tag":"date_picker","placeholder":{"tag":"plain_text","content":"日期"},"initial_date":"2024-01-02"}]}`,
+		},
+		{
+			name:    "多列布局",
+			element: NewColumnSet(NewColumn(NewMarkdown("左")), NewColumn(NewMarkdown("右")).WithWeight(2)),
+			want: `{"tag":"column_set","flex_mode":"none","columns":[` +
+				`{"tag":"column","width":"weighted","weight":1,"elements":[{"tag":"markdown","content":"左"}]},` +
+				`{"tag":"column","width":"weighted","weight":2,"elements":[{"tag":"markdown","content":"右"}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.element.Validate(); err != nil {
+				t.Fatalf("Validate() error: %v", err)
+			}
+			assertJSON(t, tt.element, tt.want)
+		})
+	}
+}
+
+func TestCardElementValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		element CardElement
+		field   string
+	}{
+		{"div缺少内容", NewDiv(nil), ""},
+		{"空markdown", NewMarkdown(""), "content"},
+		{"图片缺少key", NewImg("", "alt"), "img_key"},
+		{"备注包含非法元素", NewNote(NewHr()), "elements[0]"},
+		{"空action", NewAction(), "actions"},
+		{"按钮类型错误", NewAction(NewButton("x", "warning")), "actions[0].type"},
+		{"按钮缺少文本", NewAction(NewButton("", ButtonDefault)), "actions[0].text.content"},
+		{"下拉无选项", NewAction(NewSelectStatic("请选择")), "actions[0].options"},
+		{"日期格式错误", NewAction(NewDatePicker("日期").WithInitialDate("2024/01/02")), "actions[0].initial_date"},
+		{"空多列", NewColumnSet(), "columns"},
+		{"列中元素错误", NewColumnSet(NewColumn(NewMarkdown(""))), "columns[0].elements[0].content"},
+		{"错误的tag", &MarkdownElement{Tag: "md", Content: "x"}, "tag"},
+		{"nil 组件", (*DivElement)(nil), ""},
+		{"列中直接放文本", NewColumnSet(NewColumn(NewPlainText("x"))), "columns[0].elements[0]"},
+		{"nil 按钮", NewAction((*ButtonAction)(nil)), "actions[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.element.Validate()
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			if errs[0].Field != tt.field {
+				t.Errorf("Field = %q, want %q (%v)", errs[0].Field, tt.field, err)
+			}
+		})
+	}
+}
+
+func TestCardBuilder(t *testing.T) {
+	t.Run("构建卡片消息", func(t *testing.T) {
+		message, err := NewCardBuilder().
+			Config(CreateCardConfig(true)).
+			Header("部署完成通知", "green").
+			Div(NewPlainText("✅ 应用部署成功")).
+			Hr().
+			Markdown("**项目:** myapp").
+			Action(NewButton("查看详情", ButtonDefault).WithURL("https://deploy.example.com")).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() error: %v", err)
+		}
+
+		if message.MsgType != MessageTypeInteractive {
+			t.Errorf("MsgType = %v, want interactive", message.MsgType)
+		}
+		content := message.Content.(*InteractiveContent)
+		if len(content.Elements) != 4 {
+			t.Errorf("elements = %d, want 4", len(content.Elements))
+		}
+		if content.Header.Template != "green" {
+			t.Errorf("Template = %v, want green", content.Header.Template)
+		}
+	})
+
+	t.Run("汇总全部错误", func(t *testing.T) {
+		_, err := NewCardBuilder().
+			Header("", "red").
+			Markdown("").
+			Img("", "alt").
+			Build()
+
+		var errs ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("Build() = %v, want ValidationErrors", err)
+		}
+		if len(errs) != 3 {
+			t.Errorf("errors = %d, want 3: %v", len(errs), err)
+		}
+		for _, field := range []string{"header.title.content", "elements[0].content", "elements[1].img_key"} {
+			if !strings.Contains(err.Error(), field) {
+				t.Errorf("error should mention %s: %v", field, err)
+			}
+		}
+	})
+
+	t.Run("nil 组件", func(t *testing.T) {
+		var div *DivElement
+		_, err := NewCardBuilder().Add(div).Build()
+		if fields := fieldsOf(t, err); strings.Join(fields, ",") != "elements[0]" {
+			t.Errorf("fields = %v, want elements[0]", fields)
+		}
+	})
+
+	t.Run("文本只能放在备注中", func(t *testing.T) {
+		_, err := NewCardBuilder().Add(NewPlainText("x")).Note(NewPlainText("备注")).Elements()
+		if fields := fieldsOf(t, err); strings.Join(fields, ",") != "elements[0]" {
+			t.Errorf("fields = %v, want elements[0]", fields)
+		}
+	})
+
+	t.Run("空卡片", func(t *testing.T) {
+		if _, err := NewCardBuilder().Build(); err == nil {
+			t.Error("Expected error for empty card")
+		}
+	})
+
+	t.Run("发送卡片", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&request)
+			content := request["content"].(map[string]interface{})
+			elements := content["elements"].([]interface{})
+			if elements[0].(map[string]interface{})["tag"] != "markdown" {
+				t.Errorf("first element = %v", elements[0])
+			}
+			successHandler(w, r)
+		}))
+		defer server.Close()
+
+		sdk := New(server.URL)
+		if err := sdk.SendCard(NewCardBuilder().Markdown("hello")); err != nil {
+			t.Errorf("SendCard() error: %v", err)
+		}
+		if err := sdk.SendCard(NewCardBuilder()); err == nil {
+			t.Error("SendCard() should fail validation before sending")
+		}
+	})
+}
@@ -0,0 +1,290 @@
+package feishu
+
+const CardSchemaV2 = "2.0"
+
+// CardV2 对应飞书卡片 JSON 2.0 结构
+type CardV2 struct {
+	Schema string        `json:"schema"`
+	Config *CardV2Config `json:"config,omitempty"`
+	Header *CardV2Header `json:"header,omitempty"`
+	Body   *CardV2Body   `json:"body"`
+}
+
+type CardV2Config struct {
+	EnableForward   *bool            `json:"enable_forward,omitempty"`
+	UpdateMulti     bool             `json:"update_multi,omitempty"`
+	WidthMode       string           `json:"width_mode,omitempty"`
+	StreamingMode   bool             `json:"streaming_mode,omitempty"`
+	StreamingConfig *StreamingConfig `json:"streaming_config,omitempty"`
+	Summary         *CardSummary     `json:"summary,omitempty"`
+}
+
+// StreamingConfig 控制流式更新卡片时的打字机效果，键为 default/android/ios/pc
+type StreamingConfig struct {
+	PrintFrequencyMs map[string]int `json:"print_frequency_ms,omitempty"`
+	PrintStep        map[string]int `json:"print_step,omitempty"`
+	PrintStrategy    string         `json:"print_strategy,omitempty"`
+}
+
+type CardSummary struct {
+	Content string `json:"content"`
+}
+
+type CardV2Header struct {
+	Title    *CardText `json:"title"`
+	Subtitle *CardText `json:"subtitle,omitempty"`
+	Template string    `json:"template,omitempty"`
+}
+
+type CardV2Body struct {
+	Direction string        `json:"direction,omitempty"`
+	Padding   string        `json:"padding,omitempty"`
+	Elements  []CardElement `json:"elements"`
+}
+
+type CollapsiblePanelElement struct {
+	Tag      string                  `json:"tag"`
+	Expanded bool                    `json:"expanded"`
+	Header   *CollapsiblePanelHeader `json:"header"`
+	Elements []CardElement           `json:"elements"`
+}
+
+type CollapsiblePanelHeader struct {
+	Title *CardText `json:"title"`
+}
+
+type TableColumn struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	DataType    string `json:"data_type"`
+	Width       string `json:"width,omitempty"`
+}
+
+type TableElement struct {
+	Tag       string                   `json:"tag"`
+	PageSize  int                      `json:"page_size,omitempty"`
+	RowHeight string                   `json:"row_height,omitempty"`
+	Columns   []*TableColumn           `json:"columns"`
+	Rows      []map[string]interface{} `json:"rows"`
+}
+
+type ChartElement struct {
+	Tag         string                 `json:"tag"`
+	ChartSpec   map[string]interface{} `json:"chart_spec"`
+	AspectRatio string                 `json:"aspect_ratio,omitempty"`
+	ColorTheme  string                 `json:"color_theme,omitempty"`
+	Preview     *bool                  `json:"preview,omitempty"`
+	Height      string                 `json:"height,omitempty"`
+}
+
+type FormElement struct {
+	Tag      string        `json:"tag"`
+	Name     string        `json:"name"`
+	Elements []CardElement `json:"elements"`
+}
+
+type InputElement struct {
+	Tag          string    `json:"tag"`
+	Name         string    `json:"name"`
+	Placeholder  *CardText `json:"placeholder,omitempty"`
+	DefaultValue string    `json:"default_value,omitempty"`
+	Required     bool      `json:"required,omitempty"`
+}
+
+var tableDataTypes = map[string]bool{
+	"text":     true,
+	"lark_md":  true,
+	"number":   true,
+	"options":  true,
+	"persons":  true,
+	"date":     true,
+	"markdown": true,
+}
+
+func NewCardV2(elements ...CardElement) *CardV2 {
+	return &CardV2{
+		Schema: CardSchemaV2,
+		Body:   &CardV2Body{Elements: elements},
+	}
+}
+
+func (c *CardV2) WithHeader(title, template string) *CardV2 {
+	c.Header = &CardV2Header{Title: NewPlainText(title), Template: template}
+	return c
+}
+
+func (c *CardV2) WithConfig(config *CardV2Config) *CardV2 {
+	c.Config = config
+	return c
+}
+
+func (c *CardV2) Add(elements ...CardElement) *CardV2 {
+	if c.Body == nil {
+		c.Body = &CardV2Body{}
+	}
+	c.Body.Elements = append(c.Body.Elements, elements...)
+	return c
+}
+
+func NewCollapsiblePanel(title string, elements ...CardElement) *CollapsiblePanelElement {
+	return &CollapsiblePanelElement{
+		Tag:      "collapsible_panel",
+		Header:   &CollapsiblePanelHeader{Title: NewLarkMd(title)},
+		Elements: elements,
+	}
+}
+
+func NewTableColumn(name, displayName, dataType string) *TableColumn {
+	return &TableColumn{Name: name, DisplayName: displayName, DataType: dataType}
+}
+
+func NewTable(columns []*TableColumn, rows []map[string]interface{}) *TableElement {
+	return &TableElement{Tag: "table", Columns: columns, Rows: rows}
+}
+
+func NewChart(spec map[string]interface{}) *ChartElement {
+	return &ChartElement{Tag: "chart", ChartSpec: spec}
+}
+
+func NewForm(name string, elements ...CardElement) *FormElement {
+	return &FormElement{Tag: "form", Name: name, Elements: elements}
+}
+
+func NewInput(name, placeholder string) *InputElement {
+	return &InputElement{Tag: "input", Name: name, Placeholder: NewPlainText(placeholder)}
+}
+
+// NewSubmitButton 创建表单提交按钮
+func NewSubmitButton(name, text string) *ButtonAction {
+	button := NewButton(text, ButtonPrimary)
+	button.Name = name
+	button.FormActionType = "submit"
+	return button
+}
+
+func NewCardV2Message(card *CardV2) *Message {
+	return &Message{
+		MsgType: MessageTypeInteractive,
+		Content: card,
+	}
+}
+
+func (c *CardV2) Validate() error {
+	v := &validator{}
+	c.validate(v, "")
+	return v.err()
+}
+
+func (c *CardV2) validate(v *validator, path string) {
+	if c.Schema != CardSchemaV2 {
+		v.add(joinPath(path, "schema"), "must be %q, got %q", CardSchemaV2, c.Schema)
+	}
+	if c.Header != nil {
+		validateCardText(v, joinPath(path, "header.title"), c.Header.Title)
+		if c.Header.Subtitle != nil {
+			c.Header.Subtitle.validate(v, joinPath(path, "header.subtitle"))
+		}
+	}
+	if c.Body == nil || len(c.Body.Elements) == 0 {
+		v.add(joinPath(path, "body.elements"), "is empty")
+		return
+	}
+	validateCardV2Elements(v, joinPath(path, "body"), c.Body.Elements)
+}
+
+// 卡片 2.0 不再支持 action 和 note 组件
+func validateCardV2Elements(v *validator, path string, elements []CardElement) {
+	for i, element := range elements {
+		elementPath := indexPath(path, "elements", i)
+		switch element.(type) {
+		case nil:
+			v.add(elementPath, "is nil")
+		case *ActionElement, *NoteElement:
+			v.add(elementPath, "%T is not supported in card schema 2.0", element)
+		default:
+			validateBodyElement(v, elementPath, element)
+		}
+	}
+}
+
+func (p *CollapsiblePanelElement) Validate() error { return validateCardElement(p) }
+func (t *TableElement) Validate() error            { return validateCardElement(t) }
+func (c *ChartElement) Validate() error            { return validateCardElement(c) }
+func (f *FormElement) Validate() error             { return validateCardElement(f) }
+func (i *InputElement) Validate() error            { return validateCardElement(i) }
+
+func (p *CollapsiblePanelElement) validate(v *validator, path string) {
+	validateTag(v, path, p.Tag, "collapsible_panel")
+	if p.Header == nil {
+		v.add(joinPath(path, "header"), "is required")
+	} else {
+		validateCardText(v, joinPath(path, "header.title"), p.Header.Title)
+	}
+	if len(p.Elements) == 0 {
+		v.add(joinPath(path, "elements"), "is empty")
+	}
+	validateCardV2Elements(v, path, p.Elements)
+}
+
+func (t *TableElement) validate(v *validator, path string) {
+	validateTag(v, path, t.Tag, "table")
+	if len(t.Columns) == 0 {
+		v.add(joinPath(path, "columns"), "is empty")
+	}
+
+	names := make(map[string]bool)
+	for i, column := range t.Columns {
+		columnPath := indexPath(path, "columns", i)
+		if column == nil {
+			v.add(columnPath, "is nil")
+			continue
+		}
+		if column.Name == "" {
+			v.add(joinPath(columnPath, "name"), "is empty")
+		} else if names[column.Name] {
+			v.add(joinPath(columnPath, "name"), "duplicate column %q", column.Name)
+		}
+		names[column.Name] = true
+		if !tableDataTypes[column.DataType] {
+			v.add(joinPath(columnPath, "data_type"), "unknown data type %q", column.DataType)
+		}
+	}
+
+	for i, row := range t.Rows {
+		for key := range row {
+			if !names[key] {
+				v.add(indexPath(path, "rows", i), "unknown column %q", key)
+			}
+		}
+	}
+}
+
+func (c *ChartElement) validate(v *validator, path string) {
+	validateTag(v, path, c.Tag, "chart")
+	if len(c.ChartSpec) == 0 {
+		v.add(joinPath(path, "chart_spec"), "is empty")
+	}
+}
+
+func (f *FormElement) validate(v *validator, path string) {
+	validateTag(v, path, f.Tag, "form")
+	if f.Name == "" {
+		v.add(joinPath(path, "name"), "is empty")
+	}
+	if len(f.Elements) == 0 {
+		v.add(joinPath(path, "elements"), "is empty")
+	}
+	for i, element := range f.Elements {
+		if _, ok := element.(*FormElement); ok {
+			v.add(indexPath(path, "elements", i), "form cannot be nested")
+		}
+	}
+	validateCardV2Elements(v, path, f.Elements)
+}
+
+func (i *InputElement) validate(v *validator, path string) {
+	validateTag(v, path, i.Tag, "input")
+	if i.Name == "" {
+		v.add(joinPath(path, "name"), "is empty")
+	}
+}
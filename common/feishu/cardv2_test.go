@@ -0,0 +1,124 @@
+package feishu
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCardV2Serialization(t *testing.T) {
+	card := NewCardV2(
+		NewMarkdown("**hello**"),
+		NewCollapsiblePanel("详情", NewMarkdown("折叠内容")),
+		NewTable(
+			[]*TableColumn{NewTableColumn("host", "主机", "text"), NewTableColumn("cpu", "CPU", "number")},
+			[]map[string]interface{}{{"host": "web-01", "cpu": 95}},
+		),
+	).WithHeader("标题", "blue").WithConfig(&CardV2Config{
+		StreamingMode: true,
+		StreamingConfig: &StreamingConfig{
+			PrintFrequencyMs: map[string]int{"default": 70},
+			PrintStrategy:    "fast",
+		},
+	})
+
+	if err := card.Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+
+	assertJSON(t, NewCardV2Message(card), `{
+		"msg_type": "interactive",
+		"content": {
+			"schema": "2.0",
+			"config": {"streaming_mode": true, "streaming_config": {"print_frequency_ms": {"default": 70}, "print_strategy": "fast"}},
+			"header": {"title": {"tag": "plain_text", "content": "标题"}, "template": "blue"},
+			"body": {"elements": [
+				{"tag": "markdown", "content": "**hello**"},
+				{"tag": "collapsible_panel", "expanded": false, "header": {"title": {"tag": "lark_md", "content": "详情"}}, "elements": [{"tag": "markdown", "content": "折叠内容"}]},
+				{"tag": "table", "columns": [
+					{"name": "host", "display_name": "主机", "data_type": "text"},
+					{"name": "cpu", "display_name": "CPU", "data_type": "number"}
+				], "rows": [{"host": "web-01", "cpu": 95}]}
+			]}
+		}
+	}`)
+}
+
+func TestCardV2Components(t *testing.T) {
+	form := NewForm("feedback", NewInput("comment", "请输入"), NewSubmitButton("submit", "提交"))
+	assertJSON(t, form, `{"tag":"form","name":"feedback","elements":[
+		{"tag":"input","name":"comment","placeholder":{"tag":"plain_text","content":"请输入"}},
+		{"tag":"button","text":{"tag":"plain_text","content":"提交"},"type":"primary","name":"submit","form_action_type":"submit"}
+	]}`)
+
+	chart := NewChart(map[string]interface{}{"type": "line"})
+	assertJSON(t, chart, `{"tag":"chart","chart_spec":{"type":"line"}}`)
+
+	if err := NewCardV2(form, chart).Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
+
+func TestCardV2Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		card  *CardV2
+		field string
+	}{
+		{"空body", NewCardV2(), "body.elements"},
+		{"错误的schema", &CardV2{Schema: "1.0", Body: &CardV2Body{Elements: []CardElement{NewHr()}}}, "schema"},
+		{"不支持action", NewCardV2(NewAction(NewButton("x", ButtonDefault))), "body.elements[0]"},
+		{"空折叠面板", NewCardV2(NewCollapsiblePanel("标题")), "body.elements[0].elements"},
+		{"表格未知数据类型", NewCardV2(NewTable([]*TableColumn{NewTableColumn("a", "A", "blob")}, nil)), "body.elements[0].columns[0].data_type"},
+		{"表格重复列", NewCardV2(NewTable([]*TableColumn{NewTableColumn("a", "A", "text"), NewTableColumn("a", "B", "text")}, nil)), "body.elements[0].columns[1].name"},
+		{"表格行包含未知列", NewCardV2(NewTable([]*TableColumn{NewTableColumn("a", "A", "text")}, []map[string]interface{}{{"b": 1}})), "body.elements[0].rows[0]"},
+		{"空图表", NewCardV2(NewChart(nil)), "body.elements[0].chart_spec"},
+		{"表单缺少名称", NewCardV2(NewForm("", NewInput("x", "x"))), "body.elements[0].name"},
+		{"嵌套表单", NewCardV2(NewForm("outer", NewForm("inner", NewInput("x", "x")))), "body.elements[0].elements[0]"},
+		{"顶层文本", NewCardV2(NewPlainText("x")), "body.elements[0]"},
+		{"nil 组件", NewCardV2((*MarkdownElement)(nil)), "body.elements[0]"},
+		{"缺少标题", NewCardV2(NewHr()).WithHeader("", "red"), "header.title.content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs ValidationErrors
+			if err := tt.card.Validate(); !errors.As(err, &errs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			if errs[0].Field != tt.field {
+				t.Errorf("Field = %q, want %q (%v)", errs[0].Field, tt.field, errs)
+			}
+		})
+	}
+}
+
+func TestSendCardV2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			MsgType string `json:"msg_type"`
+			Content struct {
+				Schema string `json:"schema"`
+			} `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.MsgType != "interactive" || request.Content.Schema != "2.0" {
+			t.Errorf("unexpected request: %+v", request)
+		}
+		successHandler(w, r)
+	}))
+	defer server.Close()
+
+	sdk := New(server.URL, "secret")
+	if err := sdk.SendCardV2(NewCardV2(NewMarkdown("hello"))); err != nil {
+		t.Errorf("SendCardV2() error: %v", err)
+	}
+	if err := sdk.SendCardV2(NewCardV2()); err == nil {
+		t.Error("SendCardV2() should reject invalid cards")
+	}
+	if err := sdk.Client().SendCardV2(nil); err == nil {
+		t.Error("SendCardV2(nil) should fail")
+	}
+}
@@ -0,0 +1,66 @@
+package feishu
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("feishu: signature invalid")
+	ErrRateLimited      = errors.New("feishu: rate limited")
+	ErrKeywordMismatch  = errors.New("feishu: keyword mismatch")
+	ErrMessageTooLarge  = errors.New("feishu: message too large")
+	ErrParamInvalid     = errors.New("feishu: param invalid")
+	ErrIPNotAllowed     = errors.New("feishu: ip not allowed")
+)
+
+// 飞书自定义机器人已知错误码与哨兵错误的对应关系
+var codeErrors = map[int]error{
+	9499:  ErrRateLimited,
+	11232: ErrRateLimited,
+	19001: ErrParamInvalid,
+	19002: ErrParamInvalid,
+	19021: ErrSignatureInvalid,
+	19022: ErrIPNotAllowed,
+	19024: ErrKeywordMismatch,
+}
+
+var statusErrors = map[int]error{
+	http.StatusTooManyRequests:       ErrRateLimited,
+	http.StatusRequestEntityTooLarge: ErrMessageTooLarge,
+}
+
+// APIError 表示飞书返回的错误，包括非 200 状态码和 code 不为 0 的响应
+type APIError struct {
+	Code       int
+	Msg        string
+	HTTPStatus int
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("feishu webhook error: code=%d, msg=%s", e.Code, e.Msg)
+	}
+	return fmt.Sprintf("request failed with status: %d, body: %s", e.HTTPStatus, e.Body)
+}
+
+func (e *APIError) Unwrap() error {
+	if err, ok := codeErrors[e.Code]; ok {
+		return err
+	}
+	return statusErrors[e.HTTPStatus]
+}
+
+type sendError struct {
+	err error
+}
+
+func (e *sendError) Error() string {
+	return fmt.Sprintf("send request failed: %v", e.err)
+}
+
+func (e *sendError) Unwrap() error {
+	return e.err
+}
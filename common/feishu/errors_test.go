@@ -0,0 +1,139 @@
+package feishu
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseCode int
+		responseBody map[string]interface{}
+		wantCode     int
+		wantStatus   int
+		wantErr      error
+	}{
+		{
+			name:         "签名校验失败",
+			responseCode: 200,
+			responseBody: map[string]interface{}{"code": 19021, "msg": "sign match fail or timestamp is not within one hour from current time"},
+			wantCode:     19021,
+			wantStatus:   200,
+			wantErr:      ErrSignatureInvalid,
+		},
+		{
+			name:         "关键词校验失败",
+			responseCode: 200,
+			responseBody: map[string]interface{}{"code": 19024, "msg": "Key Words Not Found"},
+			wantCode:     19024,
+			wantStatus:   200,
+			wantErr:      ErrKeywordMismatch,
+		},
+		{
+			name:         "频率限制",
+			responseCode: 200,
+			responseBody: map[string]interface{}{"code": 11232, "msg": "frequency limited"},
+			wantCode:     11232,
+			wantStatus:   200,
+			wantErr:      ErrRateLimited,
+		},
+		{
+			name:         "请求过多",
+			responseCode: 200,
+			responseBody: map[string]interface{}{"code": 9499, "msg": "Too Many Request"},
+			wantCode:     9499,
+			wantStatus:   200,
+			wantErr:      ErrRateLimited,
+		},
+		{
+			name:         "参数错误",
+			responseCode: 200,
+			responseBody: map[string]interface{}{"code": 19001, "msg": "param invalid"},
+			wantCode:     19001,
+			wantStatus:   200,
+			wantErr:      ErrParamInvalid,
+		},
+		{
+			name:         "HTTP 429",
+			responseCode: 429,
+			responseBody: map[string]interface{}{},
+			wantStatus:   429,
+			wantErr:      ErrRateLimited,
+		},
+		{
+			name:         "HTTP 413",
+			responseCode: 413,
+			responseBody: map[string]interface{}{},
+			wantStatus:   413,
+			wantErr:      ErrMessageTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupMockServer(t, tt.responseCode, tt.responseBody)
+			defer server.Close()
+
+			err := NewClient(server.URL).SendText("test")
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Error should be *APIError, got %T: %v", err, err)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", apiErr.Code, tt.wantCode)
+			}
+			if apiErr.HTTPStatus != tt.wantStatus {
+				t.Errorf("HTTPStatus = %v, want %v", apiErr.HTTPStatus, tt.wantStatus)
+			}
+			if len(apiErr.Body) == 0 {
+				t.Error("Body should not be empty")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("errors.Is(err, %v) = false", tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	err := &APIError{Code: 19001, Msg: "param invalid", HTTPStatus: 200}
+	if !strings.HasPrefix(err.Error(), "feishu webhook error") {
+		t.Errorf("Error() = %v", err.Error())
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Error("param error should not match ErrRateLimited")
+	}
+
+	unknown := &APIError{Code: 12345, HTTPStatus: 200}
+	if errors.Unwrap(unknown) != nil {
+		t.Error("unknown code should not map to a sentinel error")
+	}
+
+	statusErr := &APIError{HTTPStatus: 500, Body: []byte("Internal Server Error")}
+	if !strings.Contains(statusErr.Error(), "status: 500") {
+		t.Errorf("Error() = %v", statusErr.Error())
+	}
+}
+
+func TestSendErrorUnwrap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	err := NewClient(url).SendText("test")
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Error("network error should not be *APIError")
+	}
+	if !strings.Contains(err.Error(), "send request failed") {
+		t.Errorf("Error() = %v", err)
+	}
+	if errors.Unwrap(err) == nil {
+		t.Error("network error should wrap the underlying error")
+	}
+}
@@ -1,6 +1,7 @@
 package feishu
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -12,6 +13,19 @@ type Client struct {
 	WebhookURL string
 	Secret     string
 	client     *resty.Client
+	transport  transportOptions
+	retry      *RetryPolicy
+	limiter    *RateLimiter
+	limitMode  RateLimitMode
+
+	maxBodySize    int
+	oversize       OversizeMode
+	truncateMarker string
+
+	skipValidation bool
+
+	clock       Clock
+	clockOffset time.Duration
 }
 
 type WebhookRequest struct {
@@ -21,12 +35,32 @@ type WebhookRequest struct {
 	Content   interface{} `json:"content"`
 }
 
-func NewClient(webhookURL string, secret ...string) *Client {
-	client := &Client{
-		WebhookURL: webhookURL,
-		client:     resty.New(),
+// UnmarshalJSON 与 Message 一样按 msg_type 解码 content
+func (r *WebhookRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Timestamp string          `json:"timestamp"`
+		Sign      string          `json:"sign"`
+		MsgType   string          `json:"msg_type"`
+		Content   json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
 	}
 
+	content, err := decodeContent(MessageType(raw.MsgType), raw.Content)
+	if err != nil {
+		return err
+	}
+	r.Timestamp = raw.Timestamp
+	r.Sign = raw.Sign
+	r.MsgType = raw.MsgType
+	r.Content = content
+	return nil
+}
+
+func NewClient(webhookURL string, secret ...string) *Client {
+	client := NewClientWithOptions(webhookURL)
+
 	if len(secret) > 0 {
 		client.Secret = secret[0]
 	}
@@ -34,35 +68,169 @@ func NewClient(webhookURL string, secret ...string) *Client {
 	return client
 }
 
+func NewClientWithOptions(webhookURL string, opts ...ClientOption) *Client {
+	client := &Client{
+		WebhookURL:     webhookURL,
+		maxBodySize:    DefaultMaxBodySize,
+		truncateMarker: DefaultTruncateMarker,
+		clock:          SystemClock,
+	}
+
+	return client.SetOptions(opts...)
+}
+
 func (c *Client) SendMessage(message *Message) error {
-	if c.Secret != "" {
-		return c.sendMessageWithSign(message)
+	return c.SendMessageContext(context.Background(), message)
+}
+
+func (c *Client) SendMessageContext(ctx context.Context, message *Message) error {
+	if !c.skipValidation {
+		if err := message.Validate(); err != nil {
+			return err
+		}
+	}
+
+	messages, err := c.fitMessage(message)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range messages {
+		if err := c.sendWithRetry(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) sendWithRetry(ctx context.Context, message *Message) error {
+	for attempt := 1; ; attempt++ {
+		// 本地限流的拒绝和等待超时直接返回，不进入退避重试
+		if err := c.acquire(ctx); err != nil {
+			return err
+		}
+		err := c.sendMessageOnce(ctx, message)
+		if err == nil || attempt >= c.retry.attempts() || !c.retry.shouldRetry(err) {
+			return err
+		}
+		if waitErr := sleepContext(ctx, c.retry.backoff(attempt)); waitErr != nil {
+			return fmt.Errorf("%w; retry aborted: %w", err, waitErr)
+		}
 	}
-	return c.sendMessageWithoutSign(message)
 }
 
 func (c *Client) SendText(text string) error {
+	return c.SendTextContext(context.Background(), text)
+}
+
+func (c *Client) SendTextContext(ctx context.Context, text string) error {
 	message := NewTextMessage(text)
-	return c.SendMessage(message)
+	return c.SendMessageContext(ctx, message)
 }
 
 func (c *Client) SendRichText(title string, content [][]RichTextElement) error {
-	message := NewRichTextMessage(title, content)
-	return c.SendMessage(message)
+	return c.SendRichTextContext(context.Background(), title, content)
+}
+
+func (c *Client) SendRichTextContext(ctx context.Context, title string, content [][]RichTextElement) error {
+	return c.SendMessageContext(ctx, NewRichTextMessage(title, content))
+}
+
+func (c *Client) SendLocalizedRichText(builder *PostBuilder) error {
+	return c.SendLocalizedRichTextContext(context.Background(), builder)
+}
+
+func (c *Client) SendLocalizedRichTextContext(ctx context.Context, builder *PostBuilder) error {
+	message, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	return c.SendMessageContext(ctx, message)
+}
+
+func (c *Client) SendMarkdownAsPost(title, markdown string) error {
+	return c.SendMarkdownAsPostContext(context.Background(), title, markdown)
+}
+
+func (c *Client) SendMarkdownAsPostContext(ctx context.Context, title, markdown string) error {
+	content := MarkdownToPost(title, markdown)
+	return c.SendRichTextContext(ctx, content.Title, content.Content)
 }
 
 func (c *Client) SendImage(imageKey string) error {
+	return c.SendImageContext(context.Background(), imageKey)
+}
+
+func (c *Client) SendImageContext(ctx context.Context, imageKey string) error {
 	message := NewImageMessage(imageKey)
-	return c.SendMessage(message)
+	return c.SendMessageContext(ctx, message)
 }
 
 func (c *Client) SendInteractive(config *CardConfig, header *CardHeader, elements []interface{}) error {
+	return c.SendInteractiveContext(context.Background(), config, header, elements)
+}
+
+func (c *Client) SendInteractiveContext(ctx context.Context, config *CardConfig, header *CardHeader, elements []interface{}) error {
 	message := NewInteractiveMessage(config, header, elements)
-	return c.SendMessage(message)
+	return c.SendMessageContext(ctx, message)
+}
+
+func (c *Client) SendCard(builder *CardBuilder) error {
+	return c.SendCardContext(context.Background(), builder)
+}
+
+func (c *Client) SendCardContext(ctx context.Context, builder *CardBuilder) error {
+	message, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	return c.SendMessageContext(ctx, message)
+}
+
+func (c *Client) SendCardV2(card *CardV2) error {
+	return c.SendCardV2Context(context.Background(), card)
+}
+
+func (c *Client) SendCardV2Context(ctx context.Context, card *CardV2) error {
+	if card == nil {
+		return fmt.Errorf("card is nil")
+	}
+	if err := card.Validate(); err != nil {
+		return err
+	}
+	return c.SendMessageContext(ctx, NewCardV2Message(card))
+}
+
+func (c *Client) SendCardTemplate(templateID, version string, vars TemplateVariables) error {
+	return c.SendCardTemplateContext(context.Background(), templateID, version, vars)
+}
+
+func (c *Client) SendCardTemplateContext(ctx context.Context, templateID, version string, vars TemplateVariables) error {
+	message, err := NewTemplateCardMessage(templateID, version, vars)
+	if err != nil {
+		return err
+	}
+	return c.SendMessageContext(ctx, message)
+}
+
+func (c *Client) now() time.Time {
+	clock := c.clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	return clock.Now().Add(c.clockOffset)
+}
+
+// 每次尝试都重新生成时间戳和签名，避免重试时签名过期
+func (c *Client) sendMessageOnce(ctx context.Context, message *Message) error {
+	if c.Secret != "" {
+		return c.sendMessageWithSign(ctx, message)
+	}
+	return c.sendMessageWithoutSign(ctx, message)
 }
 
-func (c *Client) sendMessageWithSign(message *Message) error {
-	timestamp := time.Now().Unix()
+func (c *Client) sendMessageWithSign(ctx context.Context, message *Message) error {
+	timestamp := c.now().Unix()
 	sign, err := GenSign(c.Secret, timestamp)
 	if err != nil {
 		return fmt.Errorf("generate sign failed: %w", err)
@@ -75,30 +243,31 @@ func (c *Client) sendMessageWithSign(message *Message) error {
 		Content:   message.Content,
 	}
 
-	return c.sendRequest(request)
+	return c.sendRequest(ctx, request)
 }
 
-func (c *Client) sendMessageWithoutSign(message *Message) error {
+func (c *Client) sendMessageWithoutSign(ctx context.Context, message *Message) error {
 	request := &WebhookRequest{
 		MsgType: string(message.MsgType),
 		Content: message.Content,
 	}
 
-	return c.sendRequest(request)
+	return c.sendRequest(ctx, request)
 }
 
-func (c *Client) sendRequest(request *WebhookRequest) error {
+func (c *Client) sendRequest(ctx context.Context, request *WebhookRequest) error {
 	resp, err := c.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(request).
 		Post(c.WebhookURL)
 
 	if err != nil {
-		return fmt.Errorf("send request failed: %w", err)
+		return &sendError{err: err}
 	}
 
 	if resp.StatusCode() != 200 {
-		return fmt.Errorf("request failed with status: %d, body: %s", resp.StatusCode(), resp.String())
+		return &APIError{HTTPStatus: resp.StatusCode(), Body: resp.Body()}
 	}
 
 	var result map[string]interface{}
@@ -107,7 +276,8 @@ func (c *Client) sendRequest(request *WebhookRequest) error {
 	}
 
 	if code, ok := result["code"].(float64); ok && code != 0 {
-		return fmt.Errorf("feishu webhook error: code=%v, msg=%v", result["code"], result["msg"])
+		msg, _ := result["msg"].(string)
+		return &APIError{Code: int(code), Msg: msg, HTTPStatus: resp.StatusCode(), Body: resp.Body()}
 	}
 
 	return nil
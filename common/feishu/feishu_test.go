@@ -1,8 +1,9 @@
 package feishu
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -503,3 +504,47 @@ func TestErrorHandling(t *testing.T) {
 		}
 	})
 }
+
+func TestSendMessageContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0, "msg": "success"}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	t.Run("超时中断请求", func(t *testing.T) {
+		client := NewClient(server.URL, "test-secret")
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := client.SendTextContext(ctx, "test")
+		if err == nil {
+			t.Fatal("Expected error for expired context")
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Error should wrap context.DeadlineExceeded, got: %v", err)
+		}
+		if time.Since(start) > time.Second {
+			t.Error("Request should be aborted when the context expires")
+		}
+	})
+
+	t.Run("取消后不再发送", func(t *testing.T) {
+		client := NewClient(server.URL)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := client.SendMessageContext(ctx, NewTextMessage("test"))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Error should wrap context.Canceled, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,336 @@
+// Package feishutest 提供进程内的飞书自定义机器人假服务，用于测试发送消息的代码
+package feishutest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+// 飞书自定义机器人返回的错误码
+const (
+	CodeRateLimited      = 11232
+	CodeParamInvalid     = 19001
+	CodeSignatureInvalid = 19021
+	CodeIPNotAllowed     = 19022
+	CodeKeywordMismatch  = 19024
+)
+
+const (
+	// DefaultMaxSkew 与飞书一致，签名时间戳需在当前时间一小时内
+	DefaultMaxSkew = time.Hour
+	// DefaultWaitTimeout 是 WaitFor 的默认等待时间
+	DefaultWaitTimeout = 5 * time.Second
+)
+
+// Response 描述一次脚本化的响应，Status 为 0 时使用 200
+type Response struct {
+	Status  int
+	Code    int
+	Msg     string
+	Latency time.Duration
+}
+
+// ErrorCode 返回 HTTP 200 且携带飞书错误码的响应
+func ErrorCode(code int, msg string) Response {
+	return Response{Code: code, Msg: msg}
+}
+
+type Option func(*Server)
+
+// WithSecret 要求请求携带与 secret 匹配的签名
+func WithSecret(secret string) Option {
+	return func(s *Server) {
+		s.secret = secret
+	}
+}
+
+// WithMaxSkew 设置签名时间戳允许的偏差，小于等于 0 时不检查
+func WithMaxSkew(skew time.Duration) Option {
+	return func(s *Server) {
+		s.maxSkew = skew
+	}
+}
+
+// WithKeywords 要求消息至少包含一个关键词
+func WithKeywords(keywords ...string) Option {
+	return func(s *Server) {
+		s.keywords = keywords
+	}
+}
+
+// WithRateLimit 按给定配额限流，不传参数时使用 feishu.DefaultRateLimits
+func WithRateLimit(limits ...feishu.RateLimit) Option {
+	return func(s *Server) {
+		s.limiter = feishu.NewRateLimiter(limits...)
+	}
+}
+
+// WithLatency 为每个请求增加固定延迟
+func WithLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+func WithWaitTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.waitTimeout = timeout
+	}
+}
+
+// Server 是飞书自定义机器人的假服务，会校验签名、关键词和频率限制，
+// 并记录解码后的消息供断言使用
+type Server struct {
+	URL string
+
+	server      *httptest.Server
+	secret      string
+	maxSkew     time.Duration
+	keywords    []string
+	limiter     *feishu.RateLimiter
+	latency     time.Duration
+	waitTimeout time.Duration
+
+	mu       sync.Mutex
+	script   []Response
+	requests []*feishu.WebhookRequest
+	changed  chan struct{}
+}
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		maxSkew:     DefaultMaxSkew,
+		waitTimeout: DefaultWaitTimeout,
+		changed:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+	return s
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Respond 让接下来的请求依次使用给定的响应，用完后恢复正常处理。
+// 设置了 Status 或 Code 的响应直接返回，其中成功的响应同样会记录消息；只设置 Latency 时延迟后照常检查
+func (s *Server) Respond(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append(s.script, responses...)
+}
+
+// Requests 返回已接受的请求
+func (s *Server) Requests() []*feishu.WebhookRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*feishu.WebhookRequest(nil), s.requests...)
+}
+
+// Messages 返回已接受的消息，Content 已按 msg_type 解码为具体类型
+func (s *Server) Messages() []*feishu.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messagesLocked()
+}
+
+func (s *Server) messagesLocked() []*feishu.Message {
+	messages := make([]*feishu.Message, len(s.requests))
+	for i, request := range s.requests {
+		messages[i] = &feishu.Message{MsgType: feishu.MessageType(request.MsgType), Content: request.Content}
+	}
+	return messages
+}
+
+// WaitFor 等待至少收到 n 条消息，超时返回错误
+func (s *Server) WaitFor(n int) ([]*feishu.Message, error) {
+	timer := time.NewTimer(s.waitTimeout)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		if len(s.requests) >= n {
+			messages := s.messagesLocked()
+			s.mu.Unlock()
+			return messages, nil
+		}
+		got, changed := len(s.requests), s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return nil, fmt.Errorf("feishutest: got %d messages, want %d after %s", got, n, s.waitTimeout)
+		}
+	}
+}
+
+// Reset 清空已记录的请求和未使用的脚本
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.script = nil
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	response := s.next()
+	if latency := s.latency + response.Latency; latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if r.Method != http.MethodPost {
+		writeResponse(w, Response{Status: http.StatusMethodNotAllowed})
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeResponse(w, ErrorCode(CodeParamInvalid, "read body failed"))
+		return
+	}
+	if len(body) > feishu.DefaultMaxBodySize {
+		writeResponse(w, Response{Status: http.StatusRequestEntityTooLarge})
+		return
+	}
+
+	var request feishu.WebhookRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeResponse(w, ErrorCode(CodeParamInvalid, "params error: "+err.Error()))
+		return
+	}
+	// 只设置 Latency 的脚本仍然走正常的签名、关键词和频率检查
+	if response.Status != 0 || response.Code != 0 {
+		s.record(&request, response)
+		writeResponse(w, response)
+		return
+	}
+
+	writeResponse(w, s.check(&request))
+}
+
+func (s *Server) next() Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.script) == 0 {
+		return Response{}
+	}
+	response := s.script[0]
+	s.script = s.script[1:]
+	return response
+}
+
+func (s *Server) check(request *feishu.WebhookRequest) Response {
+	if s.secret != "" {
+		if err := feishu.VerifySign(s.secret, request.Timestamp, request.Sign, s.maxSkew); err != nil {
+			return ErrorCode(CodeSignatureInvalid, "sign match fail or timestamp is not within one hour from current time")
+		}
+	}
+
+	message := &feishu.Message{MsgType: feishu.MessageType(request.MsgType), Content: request.Content}
+	if err := message.Validate(); err != nil {
+		return ErrorCode(CodeParamInvalid, "params error: "+err.Error())
+	}
+
+	if len(s.keywords) > 0 && !containsKeyword(request.Content, s.keywords) {
+		return ErrorCode(CodeKeywordMismatch, "Key Words Not Found")
+	}
+
+	if s.limiter != nil && !s.limiter.Allow() {
+		return ErrorCode(CodeRateLimited, "frequency limited")
+	}
+
+	response := Response{}
+	s.record(request, response)
+	return response
+}
+
+// record 只记录成功的请求
+func (s *Server) record(request *feishu.WebhookRequest, response Response) {
+	if response.Code != 0 || (response.Status != 0 && response.Status != http.StatusOK) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func writeResponse(w http.ResponseWriter, response Response) {
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	msg := response.Msg
+	if msg == "" && response.Code == 0 {
+		msg = "success"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if status != http.StatusOK {
+		w.Write([]byte(http.StatusText(status)))
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code": response.Code,
+		"msg":  msg,
+		"data": map[string]interface{}{},
+	})
+}
+
+// containsKeyword 在消息内容的所有字符串字段中查找关键词
+func containsKeyword(content interface{}, keywords []string) bool {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return false
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return false
+	}
+
+	var texts []string
+	collectStrings(decoded, &texts)
+	for _, text := range texts {
+		for _, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func collectStrings(v interface{}, texts *[]string) {
+	switch v := v.(type) {
+	case string:
+		*texts = append(*texts, v)
+	case []interface{}:
+		for _, item := range v {
+			collectStrings(item, texts)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectStrings(item, texts)
+		}
+	}
+}
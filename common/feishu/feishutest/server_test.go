@@ -0,0 +1,172 @@
+package feishutest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("记录解码后的消息", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		client := feishu.NewClient(server.URL)
+		if err := client.SendText("hello"); err != nil {
+			t.Fatalf("SendText() error: %v", err)
+		}
+		if err := client.SendImage("img_key"); err != nil {
+			t.Fatalf("SendImage() error: %v", err)
+		}
+
+		messages := server.Messages()
+		if len(messages) != 2 {
+			t.Fatalf("messages = %d, want 2", len(messages))
+		}
+		if text, ok := messages[0].Content.(*feishu.TextContent); !ok || text.Text != "hello" {
+			t.Errorf("messages[0] = %#v", messages[0].Content)
+		}
+		if image, ok := messages[1].Content.(*feishu.ImageContent); !ok || image.ImageKey != "img_key" {
+			t.Errorf("messages[1] = %#v", messages[1].Content)
+		}
+	})
+
+	t.Run("校验签名", func(t *testing.T) {
+		server := NewServer(WithSecret("secret"))
+		defer server.Close()
+
+		if err := feishu.NewClient(server.URL, "secret").SendText("hello"); err != nil {
+			t.Errorf("SendText() error: %v", err)
+		}
+
+		err := feishu.NewClient(server.URL, "wrong").SendText("hello")
+		if !errors.Is(err, feishu.ErrSignatureInvalid) {
+			t.Errorf("err = %v, want ErrSignatureInvalid", err)
+		}
+
+		skewed := feishu.NewClientWithOptions(server.URL, feishu.WithSecret("secret"), feishu.WithClockOffset(-2*time.Hour))
+		if err := skewed.SendText("hello"); !errors.Is(err, feishu.ErrSignatureInvalid) {
+			t.Errorf("err = %v, want ErrSignatureInvalid", err)
+		}
+
+		if n := len(server.Requests()); n != 1 {
+			t.Errorf("requests = %d, want 1", n)
+		}
+	})
+
+	t.Run("关键词检查", func(t *testing.T) {
+		server := NewServer(WithKeywords("告警"))
+		defer server.Close()
+
+		client := feishu.NewClient(server.URL)
+		if err := client.SendText("hello"); !errors.Is(err, feishu.ErrKeywordMismatch) {
+			t.Errorf("err = %v, want ErrKeywordMismatch", err)
+		}
+		content := [][]feishu.RichTextElement{{feishu.NewTextElement("CPU 告警")}}
+		if err := client.SendRichText("监控", content); err != nil {
+			t.Errorf("SendRichText() error: %v", err)
+		}
+	})
+
+	t.Run("频率限制", func(t *testing.T) {
+		server := NewServer(WithRateLimit(feishu.RateLimit{Requests: 2, Per: time.Hour}))
+		defer server.Close()
+
+		client := feishu.NewClient(server.URL)
+		for i := 0; i < 2; i++ {
+			if err := client.SendText("hello"); err != nil {
+				t.Fatalf("SendText() error: %v", err)
+			}
+		}
+		if err := client.SendText("hello"); !errors.Is(err, feishu.ErrRateLimited) {
+			t.Errorf("err = %v, want ErrRateLimited", err)
+		}
+	})
+
+	t.Run("拒绝无效消息", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		client := feishu.NewClientWithOptions(server.URL, feishu.WithoutValidation())
+		if err := client.SendText(""); !errors.Is(err, feishu.ErrParamInvalid) {
+			t.Errorf("err = %v, want ErrParamInvalid", err)
+		}
+	})
+
+	t.Run("脚本化响应", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.Respond(ErrorCode(CodeParamInvalid, "param invalid"), Response{Status: 500})
+		client := feishu.NewClient(server.URL)
+
+		if err := client.SendText("1"); !errors.Is(err, feishu.ErrParamInvalid) {
+			t.Errorf("err = %v, want ErrParamInvalid", err)
+		}
+		var apiErr *feishu.APIError
+		if err := client.SendText("2"); !errors.As(err, &apiErr) || apiErr.HTTPStatus != 500 {
+			t.Errorf("err = %v, want HTTP 500", err)
+		}
+		if err := client.SendText("3"); err != nil {
+			t.Errorf("SendText() error: %v", err)
+		}
+		if n := len(server.Messages()); n != 1 {
+			t.Errorf("messages = %d, want 1", n)
+		}
+	})
+
+	t.Run("模拟延迟", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.Respond(Response{Latency: 200 * time.Millisecond})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := feishu.NewClient(server.URL).SendTextContext(ctx, "slow"); err == nil {
+			t.Error("SendTextContext() should time out")
+		}
+	})
+
+	t.Run("延迟脚本不跳过检查", func(t *testing.T) {
+		server := NewServer(WithSecret("secret"))
+		defer server.Close()
+
+		server.Respond(Response{Latency: 10 * time.Millisecond}, Response{Latency: 10 * time.Millisecond})
+		if err := feishu.NewClient(server.URL, "wrong").SendText("hello"); !errors.Is(err, feishu.ErrSignatureInvalid) {
+			t.Errorf("err = %v, want ErrSignatureInvalid", err)
+		}
+		if err := feishu.NewClient(server.URL, "secret").SendText("hello"); err != nil {
+			t.Errorf("SendText() error: %v", err)
+		}
+		if n := len(server.Messages()); n != 1 {
+			t.Errorf("messages = %d, want 1", n)
+		}
+	})
+}
+
+func TestServerWaitFor(t *testing.T) {
+	server := NewServer(WithWaitTimeout(100 * time.Millisecond))
+	defer server.Close()
+
+	sender := feishu.NewAsyncSender(feishu.NewClient(server.URL))
+	defer sender.Close(context.Background())
+
+	for _, text := range []string{"a", "b", "c"} {
+		sender.Send(context.Background(), feishu.NewTextMessage(text), nil)
+	}
+
+	messages, err := server.WaitFor(3)
+	if err != nil {
+		t.Fatalf("WaitFor() error: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Errorf("messages = %d, want 3", len(messages))
+	}
+
+	if _, err := server.WaitFor(4); err == nil {
+		t.Error("WaitFor() should time out")
+	}
+}
@@ -53,13 +53,13 @@ func TestIntegrationComplete(t *testing.T) {
 			t.Error("签名不应为空")
 		}
 
-		content, ok := lastRequest.Content.(map[string]interface{})
+		content, ok := lastRequest.Content.(*TextContent)
 		if !ok {
-			t.Fatal("内容应为map类型")
+			t.Fatalf("内容应为*TextContent类型, 实际: %T", lastRequest.Content)
 		}
 
-		if content["text"] != "集成测试文本消息" {
-			t.Errorf("文本内容错误: %v", content["text"])
+		if content.Text != "集成测试文本消息" {
+			t.Errorf("文本内容错误: %v", content.Text)
 		}
 	})
 
@@ -115,13 +115,13 @@ func TestIntegrationComplete(t *testing.T) {
 			t.Errorf("消息类型错误: %v, 期望: image", lastRequest.MsgType)
 		}
 
-		content, ok := lastRequest.Content.(map[string]interface{})
+		content, ok := lastRequest.Content.(*ImageContent)
 		if !ok {
-			t.Fatal("内容应为map类型")
+			t.Fatalf("内容应为*ImageContent类型, 实际: %T", lastRequest.Content)
 		}
 
-		if content["image_key"] != "img_v2_integration_test_key" {
-			t.Errorf("图片键错误: %v", content["image_key"])
+		if content.ImageKey != "img_v2_integration_test_key" {
+			t.Errorf("图片键错误: %v", content.ImageKey)
 		}
 	})
 
@@ -165,21 +165,21 @@ func TestIntegrationComplete(t *testing.T) {
 			t.Errorf("消息类型错误: %v, 期望: interactive", lastRequest.MsgType)
 		}
 
-		content, ok := lastRequest.Content.(map[string]interface{})
+		content, ok := lastRequest.Content.(*InteractiveContent)
 		if !ok {
-			t.Fatal("内容应为map类型")
+			t.Fatalf("内容应为*InteractiveContent类型, 实际: %T", lastRequest.Content)
 		}
 
-		if content["config"] == nil {
+		if content.Config == nil {
 			t.Error("配置不应为空")
 		}
 
-		if content["header"] == nil {
+		if content.Header == nil || content.Header.Title.Content != "集成测试卡片" {
 			t.Error("头部不应为空")
 		}
 
-		if content["elements"] == nil {
-			t.Error("元素不应为空")
+		if len(content.Elements) != 2 {
+			t.Errorf("元素数量错误: %d", len(content.Elements))
 		}
 	})
 }
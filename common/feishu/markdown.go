@@ -0,0 +1,396 @@
+package feishu
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	mdHeadingRe    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdFenceRe      = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})[ \t]*([^ \t`]*)")
+	mdHrRe         = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdBulletRe     = regexp.MustCompile(`^([ \t]*)[-*+][ \t]+(.*)$`)
+	mdOrderedRe    = regexp.MustCompile(`^([ \t]*)(\d{1,9})[.)][ \t]+(.*)$`)
+	mdQuoteRe      = regexp.MustCompile(`^ {0,3}>[ \t]?(.*)$`)
+	mdAtRe         = regexp.MustCompile(`^<at\s+user_id\s*=\s*"([^"]*)"\s*>([^<]*)</at>`)
+	mdAutolinkRe   = regexp.MustCompile(`^<((?:https?|mailto):[^<>\s]+)>`)
+	mdLinkTargetRe = regexp.MustCompile(`^\(\s*<?([^()\s>]*)>?(?:\s+"[^"]*")?\s*\)`)
+	mdHardBreakRe  = regexp.MustCompile(`(?:  +|\\)$`)
+	mdPunctuation  = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// MarkdownToPost 将 CommonMark 文本转换为富文本内容。title 为空且文档以一级标题开头时，使用该标题作为 title
+func MarkdownToPost(title, markdown string) *PostContent {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	if title == "" {
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if m := mdHeadingRe.FindStringSubmatch(line); m != nil && len(m[1]) == 1 {
+				title = plainInline(m[2])
+				lines = lines[i+1:]
+			}
+			break
+		}
+	}
+
+	var rows [][]RichTextElement
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		rows = append(rows, parseParagraph(paragraph)...)
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if m := mdFenceRe.FindStringSubmatch(line); m != nil {
+			flush()
+			fence := m[1]
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) && strings.Trim(strings.TrimSpace(lines[i]), fence[:1]) == "" {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			// 只有空行的代码块渲染后 text 为空，无法通过校验
+			if strings.TrimSpace(strings.Join(code, "\n")) != "" {
+				rows = append(rows, []RichTextElement{NewCodeBlockElement(m[2], strings.Join(code, "\n"))})
+			}
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if mdHrRe.MatchString(line) {
+			flush()
+			rows = append(rows, []RichTextElement{NewHrElement()})
+			continue
+		}
+
+		if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			if row := parseInline(m[2], []TextStyle{StyleBold}); len(row) > 0 {
+				rows = append(rows, row)
+			}
+			continue
+		}
+
+		if m := mdBulletRe.FindStringSubmatch(line); m != nil {
+			flush()
+			rows = append(rows, listItem(listIndent(m[1])+"• ", m[2]))
+			continue
+		}
+
+		if m := mdOrderedRe.FindStringSubmatch(line); m != nil {
+			flush()
+			rows = append(rows, listItem(listIndent(m[1])+m[2]+". ", m[3]))
+			continue
+		}
+
+		if m := mdQuoteRe.FindStringSubmatch(line); m != nil {
+			flush()
+			row := append([]RichTextElement{NewTextElement("┃ ")}, parseInline(m[1], []TextStyle{StyleItalic})...)
+			rows = append(rows, row)
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+	flush()
+
+	return &PostContent{Title: title, Content: rows}
+}
+
+// 段落内的软换行按 CommonMark 合并为空格，行尾两个空格或反斜杠表示硬换行
+func parseParagraph(lines []string) [][]RichTextElement {
+	var rows [][]RichTextElement
+	var current []string
+
+	for _, line := range lines {
+		hardBreak := mdHardBreakRe.MatchString(line)
+		current = append(current, strings.TrimSpace(mdHardBreakRe.ReplaceAllString(line, "")))
+		if hardBreak {
+			rows = appendRow(rows, parseInline(strings.Join(current, " "), nil))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		rows = appendRow(rows, parseInline(strings.Join(current, " "), nil))
+	}
+	return rows
+}
+
+// 只有硬换行标记的行解析为空行，序列化后是 null，飞书无法接受
+func appendRow(rows [][]RichTextElement, row []RichTextElement) [][]RichTextElement {
+	if len(row) == 0 {
+		return rows
+	}
+	return append(rows, row)
+}
+
+func listIndent(indent string) string {
+	width := len(strings.ReplaceAll(indent, "\t", "    "))
+	return strings.Repeat("  ", width/2)
+}
+
+func listItem(marker, text string) []RichTextElement {
+	return mergeText(append([]RichTextElement{NewTextElement(marker)}, parseInline(text, nil)...))
+}
+
+func plainInline(text string) string {
+	var sb strings.Builder
+	for _, element := range parseInline(text, nil) {
+		sb.WriteString(element.Text)
+	}
+	return sb.String()
+}
+
+func parseInline(text string, styles []TextStyle) []RichTextElement {
+	var elements []RichTextElement
+	var buf strings.Builder
+
+	flush := func() {
+		if buf.Len() > 0 {
+			elements = append(elements, NewTextElement(buf.String(), styles...))
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(mdPunctuation, text[i+1]) >= 0:
+			buf.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			n := countRun(rest, '`')
+			if end := strings.Index(rest[n:], rest[:n]); end >= 0 {
+				buf.WriteString(rest[:n+end+n])
+				i += n + end + n
+				continue
+			}
+			buf.WriteString(rest[:n])
+			i += n
+			continue
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__") || strings.HasPrefix(rest, "~~"):
+			delim := rest[:2]
+			style := StyleBold
+			if delim == "~~" {
+				style = StyleLineThrough
+			}
+			if end := findClosing(text, i+2, delim); end > i+2 && canOpen(text, i, delim) {
+				flush()
+				elements = append(elements, parseInline(text[i+2:end], withStyle(styles, style))...)
+				i = end + 2
+				continue
+			}
+
+		case c == '*' || c == '_':
+			delim := rest[:1]
+			if end := findClosing(text, i+1, delim); end > i+1 && canOpen(text, i, delim) {
+				flush()
+				elements = append(elements, parseInline(text[i+1:end], withStyle(styles, StyleItalic))...)
+				i = end + 1
+				continue
+			}
+
+		case c == '[':
+			if closeBracket := matchBracket(text, i); closeBracket > 0 {
+				if m := mdLinkTargetRe.FindStringSubmatch(text[closeBracket+1:]); m != nil && m[1] != "" {
+					flush()
+					label := plainInline(text[i+1 : closeBracket])
+					if strings.TrimSpace(label) == "" {
+						// 空链接文本在飞书中无法点击，改用地址作为文本
+						label = m[1]
+					}
+					linkStyles := stylesIn(text[i+1:closeBracket], styles)
+					elements = append(elements, NewLinkElement(label, m[1], linkStyles...))
+					i = closeBracket + 1 + len(m[0])
+					continue
+				}
+			}
+
+		case c == '<':
+			if m := mdAtRe.FindStringSubmatch(rest); m != nil {
+				flush()
+				elements = append(elements, NewAtElement(m[1], m[2]))
+				i += len(m[0])
+				continue
+			}
+			if m := mdAutolinkRe.FindStringSubmatch(rest); m != nil {
+				flush()
+				elements = append(elements, NewLinkElement(m[1], m[1], styles...))
+				i += len(m[0])
+				continue
+			}
+
+		case c == '@' && strings.HasPrefix(rest, "@all") && wordBoundaryBefore(text, i) && wordBoundaryAfter(text, i+4):
+			flush()
+			elements = append(elements, NewAtAllElement())
+			i += 4
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		buf.WriteString(rest[:size])
+		i += size
+	}
+	flush()
+
+	return mergeText(elements)
+}
+
+func countRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+func findClosing(text string, from int, delim string) int {
+	for i := from; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case text[i] == '`':
+			n := countRun(text[i:], '`')
+			if end := strings.Index(text[i+n:], text[i:i+n]); end >= 0 {
+				i += n + end + n - 1
+			}
+		case strings.HasPrefix(text[i:], delim):
+			if len(delim) == 1 && i+1 < len(text) && text[i+1] == delim[0] {
+				// 跳过成对的 ** 或 __，交给外层处理
+				end := findClosing(text, i+2, delim+delim)
+				if end < 0 {
+					return -1
+				}
+				i = end + 1
+				continue
+			}
+			if unicode.IsSpace(rune(text[i-1])) {
+				continue
+			}
+			if delim[0] == '_' && !wordBoundaryAfter(text, i+len(delim)) {
+				continue
+			}
+			// ***x*** 这类连续定界符取最右侧的两个，留给内层斜体
+			if run := countRun(text[i:], delim[0]); len(delim) == 2 && run > 2 {
+				return i + run - 2
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// 左侧定界符后不能紧跟空白；下划线还要求位于单词边界，避免误伤 snake_case
+func canOpen(text string, i int, delim string) bool {
+	next := i + len(delim)
+	if next >= len(text) || unicode.IsSpace(rune(text[next])) {
+		return false
+	}
+	if delim[0] == '_' && !wordBoundaryBefore(text, i) {
+		return false
+	}
+	return true
+}
+
+func matchBracket(text string, open int) int {
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func wordBoundaryBefore(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+func wordBoundaryAfter(text string, i int) bool {
+	if i >= len(text) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+func withStyle(styles []TextStyle, style TextStyle) []TextStyle {
+	for _, s := range styles {
+		if s == style {
+			return styles
+		}
+	}
+	return append(append([]TextStyle(nil), styles...), style)
+}
+
+// 链接只能携带一组样式，取链接文本整体包裹的强调样式
+func stylesIn(label string, styles []TextStyle) []TextStyle {
+	elements := parseInline(label, styles)
+	if len(elements) != 1 {
+		return styles
+	}
+	result := make([]TextStyle, len(elements[0].Style))
+	for i, style := range elements[0].Style {
+		result[i] = TextStyle(style)
+	}
+	return result
+}
+
+func mergeText(elements []RichTextElement) []RichTextElement {
+	var merged []RichTextElement
+	for _, element := range elements {
+		if n := len(merged); n > 0 && element.Tag == RichTextTagText && merged[n-1].Tag == RichTextTagText &&
+			!element.UnEscape && !merged[n-1].UnEscape && sameStyle(element.Style, merged[n-1].Style) {
+			merged[n-1].Text += element.Text
+			continue
+		}
+		merged = append(merged, element)
+	}
+	return merged
+}
+
+func sameStyle(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
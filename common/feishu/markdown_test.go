@@ -0,0 +1,124 @@
+package feishu
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMarkdownInline(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{"纯文本", "hello world", `[[{"tag":"text","text":"hello world"}]]`},
+		{"加粗", "a **bold** b", `[[{"tag":"text","text":"a "},{"tag":"text","text":"bold","style":["bold"]},{"tag":"text","text":" b"}]]`},
+		{"斜体", "*it* and _it_", `[[{"tag":"text","text":"it","style":["italic"]},{"tag":"text","text":" and "},{"tag":"text","text":"it","style":["italic"]}]]`},
+		{"嵌套样式", "***both***", `[[{"tag":"text","text":"both","style":["bold","italic"]}]]`},
+		{"删除线", "~~old~~", `[[{"tag":"text","text":"old","style":["lineThrough"]}]]`},
+		{"下划线变量名", "use snake_case_name here", `[[{"tag":"text","text":"use snake_case_name here"}]]`},
+		{"行内代码", "run `go test ./...` now", `[[{"tag":"text","text":"run ` + "`go test ./...`" + ` now"}]]`},
+		{"代码中的星号", "`a*b*c`", `[[{"tag":"text","text":"` + "`a*b*c`" + `"}]]`},
+		{"链接", "see [docs](https://example.com)", `[[{"tag":"text","text":"see "},{"tag":"a","text":"docs","href":"https://example.com"}]]`},
+		{"加粗链接", "[**docs**](https://example.com)", `[[{"tag":"a","text":"docs","href":"https://example.com","style":["bold"]}]]`},
+		{"空链接文本", "[](https://example.com)", `[[{"tag":"a","text":"https://example.com","href":"https://example.com"}]]`},
+		{"只有硬换行的行", "a\\\n\\\nb", `[[{"tag":"text","text":"a"}],[{"tag":"text","text":"b"}]]`},
+		{"自动链接", "<https://example.com>", `[[{"tag":"a","text":"https://example.com","href":"https://example.com"}]]`},
+		{"@所有人", "ping @all now", `[[{"tag":"text","text":"ping "},{"tag":"at","user_id":"all","user_name":"所有人"},{"tag":"text","text":" now"}]]`},
+		{"@用户", `cc <at user_id="ou_123">张三</at>`, `[[{"tag":"text","text":"cc "},{"tag":"at","user_id":"ou_123","user_name":"张三"}]]`},
+		{"邮箱不是@", "mail me@all.com", `[[{"tag":"text","text":"mail me@all.com"}]]`},
+		{"转义", `\*not italic\*`, `[[{"tag":"text","text":"*not italic*"}]]`},
+		{"未闭合", "2 * 3 = 6", `[[{"tag":"text","text":"2 * 3 = 6"}]]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSON(t, MarkdownToPost("t", tt.md).Content, tt.want)
+		})
+	}
+}
+
+func TestMarkdownBlocks(t *testing.T) {
+	md := "# Release v1.2.0\n" +
+		"\n" +
+		"## Features\n" +
+		"- add **retry**\n" +
+		"  - nested item\n" +
+		"1. first\n" +
+		"2. second\n" +
+		"\n" +
+		"soft wrapped\n" +
+		"paragraph  \n" +
+		"hard break\n" +
+		"\n" +
+		"> quoted\n" +
+		"\n" +
+		"---\n" +
+		"```go\n" +
+		"func main() {\n" +
+		"\n" +
+		"}\n" +
+		"```\n"
+
+	content := MarkdownToPost("", md)
+	if content.Title != "Release v1.2.0" {
+		t.Errorf("Title = %q, want first heading", content.Title)
+	}
+
+	assertJSON(t, content.Content, `[
+		[{"tag":"text","text":"Features","style":["bold"]}],
+		[{"tag":"text","text":"• add "},{"tag":"text","text":"retry","style":["bold"]}],
+		[{"tag":"text","text":"  • nested item"}],
+		[{"tag":"text","text":"1. first"}],
+		[{"tag":"text","text":"2. second"}],
+		[{"tag":"text","text":"soft wrapped paragraph"}],
+		[{"tag":"text","text":"hard break"}],
+		[{"tag":"text","text":"┃ "},{"tag":"text","text":"quoted","style":["italic"]}],
+		[{"tag":"hr"}],
+		[{"tag":"code_block","language":"go","text":"func main() {\n\n}"}]
+	]`)
+
+	if err := content.Validate(); err != nil {
+		t.Errorf("converted content should be valid: %v", err)
+	}
+}
+
+func TestMarkdownBlankCodeBlock(t *testing.T) {
+	content := MarkdownToPost("t", "before\n```\n\n  \n```\nafter")
+	assertJSON(t, content.Content, `[[{"tag":"text","text":"before"}],[{"tag":"text","text":"after"}]]`)
+	if err := content.Validate(); err != nil {
+		t.Errorf("converted content should be valid: %v", err)
+	}
+}
+
+func TestMarkdownExplicitTitle(t *testing.T) {
+	content := MarkdownToPost("CI", "# Heading\ntext")
+	if content.Title != "CI" {
+		t.Errorf("Title = %q, want CI", content.Title)
+	}
+	if len(content.Content) != 2 {
+		t.Errorf("heading should stay in content when title is given, got %d rows", len(content.Content))
+	}
+}
+
+func TestSendMarkdownAsPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			MsgType string          `json:"msg_type"`
+			Content RichTextContent `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.MsgType != "post" || request.Content.Post.ZhCn.Title != "CI 结果" {
+			t.Errorf("unexpected request: %+v", request)
+		}
+		successHandler(w, r)
+	}))
+	defer server.Close()
+
+	sdk := New(server.URL, "secret")
+	if err := sdk.SendMarkdownAsPost("CI 结果", "**通过** 42 个测试"); err != nil {
+		t.Errorf("SendMarkdownAsPost() error: %v", err)
+	}
+}
@@ -1,5 +1,11 @@
 package feishu
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 type MessageType string
 
 const (
@@ -15,6 +21,68 @@ type Message struct {
 	Content interface{} `json:"content"`
 }
 
+// UnmarshalJSON 按 msg_type 将 content 解码为对应的内容类型，
+// 卡片 2.0 和未知类型的内容保留为 json.RawMessage
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		MsgType MessageType     `json:"msg_type"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := decodeContent(raw.MsgType, raw.Content)
+	if err != nil {
+		return err
+	}
+	m.MsgType = raw.MsgType
+	m.Content = content
+	return nil
+}
+
+func decodeContent(msgType MessageType, data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var content interface{}
+	switch msgType {
+	case MessageTypeText:
+		content = &TextContent{}
+	case MessageTypeRichText:
+		content = &RichTextContent{}
+	case MessageTypeImage:
+		content = &ImageContent{}
+	case MessageTypeShareChat:
+		content = &ShareChatContent{}
+	case MessageTypeInteractive:
+		var probe struct {
+			Type   string `json:"type"`
+			Schema string `json:"schema"`
+		}
+		if err := json.Unmarshal(data, &probe); err != nil {
+			return nil, fmt.Errorf("decode %s content failed: %w", msgType, err)
+		}
+		switch {
+		case probe.Type == "template":
+			content = &TemplateCardContent{}
+		case probe.Schema == CardSchemaV2:
+			// 卡片 2.0 的组件是接口类型，无法还原，保留原始 JSON
+			return append(json.RawMessage(nil), data...), nil
+		default:
+			content = &InteractiveContent{}
+		}
+	default:
+		return append(json.RawMessage(nil), data...), nil
+	}
+
+	if err := json.Unmarshal(data, content); err != nil {
+		return nil, fmt.Errorf("decode %s content failed: %w", msgType, err)
+	}
+	return content, nil
+}
+
 type TextContent struct {
 	Text string `json:"text"`
 }
@@ -26,6 +94,9 @@ type RichTextContent struct {
 type Post struct {
 	ZhCn *PostContent `json:"zh_cn,omitempty"`
 	EnUs *PostContent `json:"en_us,omitempty"`
+	JaJp *PostContent `json:"ja_jp,omitempty"`
+	ZhHk *PostContent `json:"zh_hk,omitempty"`
+	ZhTw *PostContent `json:"zh_tw,omitempty"`
 }
 
 type PostContent struct {
@@ -34,12 +105,17 @@ type PostContent struct {
 }
 
 type RichTextElement struct {
-	Tag      string `json:"tag"`
-	Text     string `json:"text,omitempty"`
-	Href     string `json:"href,omitempty"`
-	UserId   string `json:"user_id,omitempty"`
-	UserName string `json:"user_name,omitempty"`
-	ImageKey string `json:"image_key,omitempty"`
+	Tag       string   `json:"tag"`
+	Text      string   `json:"text,omitempty"`
+	UnEscape  bool     `json:"un_escape,omitempty"`
+	Style     []string `json:"style,omitempty"`
+	Href      string   `json:"href,omitempty"`
+	UserId    string   `json:"user_id,omitempty"`
+	UserName  string   `json:"user_name,omitempty"`
+	ImageKey  string   `json:"image_key,omitempty"`
+	FileKey   string   `json:"file_key,omitempty"`
+	EmojiType string   `json:"emoji_type,omitempty"`
+	Language  string   `json:"language,omitempty"`
 }
 
 type InteractiveContent struct {
@@ -70,6 +146,35 @@ type ShareChatContent struct {
 	ShareChatId string `json:"share_chat_id"`
 }
 
+var (
+	ErrEmptyTemplateID       = errors.New("feishu: template id is empty")
+	ErrEmptyTemplateVariable = errors.New("feishu: template variable name is empty")
+)
+
+// TemplateVariables 是卡片模板变量，值可以是字符串、数字、布尔值或用于循环的 []TemplateVariables
+type TemplateVariables map[string]interface{}
+
+// NewTemplateVariables 创建空的模板变量，零值为 nil，不能直接调用 Set
+func NewTemplateVariables() TemplateVariables {
+	return make(TemplateVariables)
+}
+
+func (v TemplateVariables) Set(name string, value interface{}) TemplateVariables {
+	v[name] = value
+	return v
+}
+
+type TemplateCardContent struct {
+	Type string            `json:"type"`
+	Data *TemplateCardData `json:"data"`
+}
+
+type TemplateCardData struct {
+	TemplateID          string            `json:"template_id"`
+	TemplateVersionName string            `json:"template_version_name,omitempty"`
+	TemplateVariable    TemplateVariables `json:"template_variable,omitempty"`
+}
+
 func NewTextMessage(text string) *Message {
 	return &Message{
 		MsgType: MessageTypeText,
@@ -120,4 +225,28 @@ func NewShareChatMessage(shareChatId string) *Message {
 			ShareChatId: shareChatId,
 		},
 	}
-}
\ No newline at end of file
+}
+
+// NewTemplateCardMessage 创建引用卡片搭建工具中已发布模板的卡片消息，version 为空时使用最新版本
+func NewTemplateCardMessage(templateID, version string, vars TemplateVariables) (*Message, error) {
+	if templateID == "" {
+		return nil, ErrEmptyTemplateID
+	}
+	for name := range vars {
+		if name == "" {
+			return nil, ErrEmptyTemplateVariable
+		}
+	}
+
+	return &Message{
+		MsgType: MessageTypeInteractive,
+		Content: &TemplateCardContent{
+			Type: "template",
+			Data: &TemplateCardData{
+				TemplateID:          templateID,
+				TemplateVersionName: version,
+				TemplateVariable:    vars,
+			},
+		},
+	}, nil
+}
@@ -2,6 +2,8 @@ package feishu
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -342,3 +344,125 @@ func TestComplexRichTextMessage(t *testing.T) {
 		t.Errorf("content array length = %v, want 3", len(contentArray))
 	}
 }
+
+func TestNewTemplateCardMessage(t *testing.T) {
+	t.Run("模板卡片序列化", func(t *testing.T) {
+		vars := NewTemplateVariables().
+			Set("title", "发布通知").
+			Set("count", 3).
+			Set("items", []TemplateVariables{{"name": "a"}, {"name": "b"}})
+
+		msg, err := NewTemplateCardMessage("AAqk1234", "1.0.2", vars)
+		if err != nil {
+			t.Fatalf("NewTemplateCardMessage() error: %v", err)
+		}
+		if msg.MsgType != MessageTypeInteractive {
+			t.Errorf("MsgType = %v, want %v", msg.MsgType, MessageTypeInteractive)
+		}
+
+		assertJSON(t, msg, `{"msg_type":"interactive","content":{"type":"template","data":{
+			"template_id":"AAqk1234",
+			"template_version_name":"1.0.2",
+			"template_variable":{"title":"发布通知","count":3,"items":[{"name":"a"},{"name":"b"}]}
+		}}}`)
+	})
+
+	t.Run("省略版本和变量", func(t *testing.T) {
+		msg, err := NewTemplateCardMessage("AAqk1234", "", nil)
+		if err != nil {
+			t.Fatalf("NewTemplateCardMessage() error: %v", err)
+		}
+		assertJSON(t, msg, `{"msg_type":"interactive","content":{"type":"template","data":{"template_id":"AAqk1234"}}}`)
+	})
+
+	t.Run("模板ID为空", func(t *testing.T) {
+		if _, err := NewTemplateCardMessage("", "1.0.0", nil); !errors.Is(err, ErrEmptyTemplateID) {
+			t.Errorf("error = %v, want ErrEmptyTemplateID", err)
+		}
+	})
+
+	t.Run("变量名为空", func(t *testing.T) {
+		if _, err := NewTemplateCardMessage("AAqk1234", "", TemplateVariables{"": "x"}); !errors.Is(err, ErrEmptyTemplateVariable) {
+			t.Errorf("error = %v, want ErrEmptyTemplateVariable", err)
+		}
+	})
+}
+
+func TestMessageUnmarshalJSON(t *testing.T) {
+	template, _ := NewTemplateCardMessage("AAq_123", "1.0.0", TemplateVariables{"name": "feishu"})
+	messages := []*Message{
+		NewTextMessage("hello"),
+		NewRichTextMessage("标题", [][]RichTextElement{
+			{NewTextElement("加粗", StyleBold), NewLinkElement("链接", "https://example.com")},
+			{NewAtElement("ou_123", "张三"), NewCodeBlockElement("go", "fmt.Println()")},
+		}),
+		NewInteractiveMessage(CreateCardConfig(true), CreateCardHeader("卡片", "blue"), []interface{}{
+			map[string]interface{}{"tag": "hr"},
+		}),
+		NewImageMessage("img_key"),
+		NewShareChatMessage("oc_123"),
+		template,
+	}
+
+	for _, message := range messages {
+		t.Run(string(message.MsgType), func(t *testing.T) {
+			data, err := json.Marshal(message)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+
+			var decoded Message
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if !reflect.DeepEqual(&decoded, message) {
+				t.Errorf("decoded = %#v, want %#v", decoded.Content, message.Content)
+			}
+		})
+	}
+
+	t.Run("卡片 2.0 保留原始 JSON", func(t *testing.T) {
+		data, _ := json.Marshal(NewCardV2Message(NewCardV2().Add(NewMarkdown("hi"))))
+		var decoded Message
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal() error: %v", err)
+		}
+		raw, ok := decoded.Content.(json.RawMessage)
+		if !ok {
+			t.Fatalf("Content = %T, want json.RawMessage", decoded.Content)
+		}
+		again, _ := json.Marshal(&decoded)
+		if string(again) != string(data) {
+			t.Errorf("re-encoded = %s, want %s (raw %s)", again, data, raw)
+		}
+	})
+
+	t.Run("未知类型保留原始 JSON", func(t *testing.T) {
+		var decoded Message
+		if err := json.Unmarshal([]byte(`{"msg_type":"audio","content":{"file_key":"f"}}`), &decoded); err != nil {
+			t.Fatalf("Unmarshal() error: %v", err)
+		}
+		if _, ok := decoded.Content.(json.RawMessage); !ok {
+			t.Errorf("Content = %T, want json.RawMessage", decoded.Content)
+		}
+	})
+
+	t.Run("内容格式错误", func(t *testing.T) {
+		var decoded Message
+		if err := json.Unmarshal([]byte(`{"msg_type":"text","content":{"text":1}}`), &decoded); err == nil {
+			t.Error("Unmarshal() should fail for invalid text content")
+		}
+	})
+
+	t.Run("WebhookRequest", func(t *testing.T) {
+		data := []byte(`{"timestamp":"1700000000","sign":"abc","msg_type":"image","content":{"image_key":"img"}}`)
+		var request WebhookRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			t.Fatalf("Unmarshal() error: %v", err)
+		}
+		want := WebhookRequest{Timestamp: "1700000000", Sign: "abc", MsgType: "image", Content: &ImageContent{ImageKey: "img"}}
+		if !reflect.DeepEqual(request, want) {
+			t.Errorf("request = %+v, want %+v", request, want)
+		}
+	})
+}
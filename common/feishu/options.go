@@ -0,0 +1,160 @@
+package feishu
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type ClientOption func(*Client)
+
+// SetOptions 应用选项。传输相关的选项只记录设置，全部选项执行完后再统一构建 HTTP 客户端，因此与顺序无关
+func (c *Client) SetOptions(opts ...ClientOption) *Client {
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.client = c.transport.build()
+	return c
+}
+
+// transportOptions 记录 HTTP 客户端相关的设置
+type transportOptions struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	proxyURL   string
+	tlsConfig  *tls.Config
+	headers    map[string]string
+}
+
+func (t *transportOptions) build() *resty.Client {
+	client := resty.New()
+	if t.httpClient != nil {
+		client = resty.NewWithClient(t.httpClient)
+	}
+	if t.timeout > 0 {
+		client.SetTimeout(t.timeout)
+	}
+	if t.proxyURL != "" {
+		client.SetProxy(t.proxyURL)
+	}
+	if t.tlsConfig != nil {
+		client.SetTLSClientConfig(t.tlsConfig)
+	}
+	client.SetHeaders(t.headers)
+	return client
+}
+
+func (t *transportOptions) setHeader(key, value string) {
+	if t.headers == nil {
+		t.headers = make(map[string]string)
+	}
+	t.headers[key] = value
+}
+
+func WithSecret(secret string) ClientOption {
+	return func(c *Client) {
+		c.Secret = secret
+	}
+}
+
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit 启用按 webhook 地址共享的默认限流器
+func WithRateLimit(mode RateLimitMode) ClientOption {
+	return func(c *Client) {
+		c.limiter = SharedRateLimiter(c.WebhookURL)
+		c.limitMode = mode
+	}
+}
+
+func WithRateLimiter(limiter *RateLimiter, mode RateLimitMode) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+		c.limitMode = mode
+	}
+}
+
+// WithMaxBodySize 设置请求体大小上限，小于等于 0 时不检查
+func WithMaxBodySize(size int) ClientOption {
+	return func(c *Client) {
+		c.maxBodySize = size
+	}
+}
+
+func WithOversizeMode(mode OversizeMode) ClientOption {
+	return func(c *Client) {
+		c.oversize = mode
+	}
+}
+
+func WithTruncateMarker(marker string) ClientOption {
+	return func(c *Client) {
+		c.truncateMarker = marker
+	}
+}
+
+// WithoutValidation 关闭发送前的 Message.Validate 检查
+func WithoutValidation() ClientOption {
+	return func(c *Client) {
+		c.skipValidation = true
+	}
+}
+
+// WithClock 设置生成签名时间戳使用的时钟
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// WithClockOffset 校正已知的时钟偏差，作用于 WithClock 设置的时钟，与选项顺序无关
+func WithClockOffset(offset time.Duration) ClientOption {
+	return func(c *Client) {
+		c.clockOffset = offset
+	}
+}
+
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.transport.timeout = timeout
+	}
+}
+
+// WithHTTPClient 替换底层的 HTTP 客户端，超时、代理、TLS 和请求头等选项会应用在它之上
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.transport.httpClient = httpClient
+	}
+}
+
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *Client) {
+		c.transport.proxyURL = proxyURL
+	}
+}
+
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.transport.tlsConfig = config
+	}
+}
+
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.transport.setHeader("User-Agent", userAgent)
+	}
+}
+
+func WithBaseHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		for key, value := range headers {
+			c.transport.setHeader(key, value)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package feishu
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingTransport struct {
+	calls int32
+	base  http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.calls, 1)
+	return t.base.RoundTrip(req)
+}
+
+func successHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(200)
+	w.Write([]byte(`{"code": 0, "msg": "success"}`))
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	t.Run("默认选项", func(t *testing.T) {
+		client := NewClientWithOptions("https://example.com/webhook")
+		if client.WebhookURL != "https://example.com/webhook" {
+			t.Errorf("WebhookURL = %v", client.WebhookURL)
+		}
+		if client.Secret != "" {
+			t.Errorf("Secret = %v, want empty", client.Secret)
+		}
+		if client.client == nil {
+			t.Error("HTTP client should not be nil")
+		}
+	})
+
+	t.Run("签名密钥", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request WebhookRequest
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Sign == "" {
+				t.Error("Sign should not be empty")
+			}
+			successHandler(w, r)
+		}))
+		defer server.Close()
+
+		client := NewClientWithOptions(server.URL, WithSecret("test-secret"))
+		if client.Secret != "test-secret" {
+			t.Errorf("Secret = %v, want test-secret", client.Secret)
+		}
+		if err := client.SendText("test"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("请求头与UserAgent", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("User-Agent"); got != "feishu-test/1.0" {
+				t.Errorf("User-Agent = %v", got)
+			}
+			if got := r.Header.Get("X-Env"); got != "test" {
+				t.Errorf("X-Env = %v", got)
+			}
+			if got := r.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %v", got)
+			}
+			successHandler(w, r)
+		}))
+		defer server.Close()
+
+		client := NewClientWithOptions(server.URL,
+			WithUserAgent("feishu-test/1.0"),
+			WithBaseHeaders(map[string]string{"X-Env": "test"}),
+		)
+		if err := client.SendText("test"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("超时", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+
+		client := NewClientWithOptions(server.URL, WithTimeout(50*time.Millisecond))
+		if err := client.SendText("test"); err == nil {
+			t.Error("Expected timeout error")
+		}
+	})
+
+	t.Run("自定义HTTP客户端", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(successHandler))
+		defer server.Close()
+
+		transport := &countingTransport{base: http.DefaultTransport}
+		client := NewClientWithOptions(server.URL, WithHTTPClient(&http.Client{Transport: transport}))
+		if err := client.SendText("test"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if atomic.LoadInt32(&transport.calls) != 1 {
+			t.Errorf("transport calls = %d, want 1", transport.calls)
+		}
+	})
+
+	t.Run("传输选项与顺序无关", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("User-Agent"); got != "feishu-test/1.0" {
+				t.Errorf("User-Agent = %v", got)
+			}
+			if got := r.Header.Get("X-Env"); got != "test" {
+				t.Errorf("X-Env = %v", got)
+			}
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+
+		transport := &countingTransport{base: http.DefaultTransport}
+		client := NewClientWithOptions(server.URL,
+			WithTimeout(50*time.Millisecond),
+			WithUserAgent("feishu-test/1.0"),
+			WithBaseHeaders(map[string]string{"X-Env": "test"}),
+			WithHTTPClient(&http.Client{Transport: transport}),
+		)
+		start := time.Now()
+		if err := client.SendText("test"); err == nil {
+			t.Error("Expected timeout error")
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("timeout set before WithHTTPClient was lost, took %v", elapsed)
+		}
+		if atomic.LoadInt32(&transport.calls) != 1 {
+			t.Errorf("transport calls = %d, want 1", transport.calls)
+		}
+	})
+
+	t.Run("代理", func(t *testing.T) {
+		var proxied int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&proxied, 1)
+			if r.URL.Host != "feishu.invalid" {
+				t.Errorf("proxied host = %v", r.URL.Host)
+			}
+			successHandler(w, r)
+		}))
+		defer proxy.Close()
+
+		client := NewClientWithOptions("http://feishu.invalid/hook", WithProxy(proxy.URL))
+		if err := client.SendText("test"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if atomic.LoadInt32(&proxied) != 1 {
+			t.Error("request should go through the proxy")
+		}
+	})
+
+	t.Run("TLS配置", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(successHandler))
+		defer server.Close()
+
+		if err := NewClient(server.URL).SendText("test"); err == nil {
+			t.Error("Expected certificate error without TLS config")
+		}
+
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+		client := NewClientWithOptions(server.URL, WithTLSConfig(&tls.Config{RootCAs: pool}))
+		if err := client.SendText("test"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("SDK选项构造", func(t *testing.T) {
+		sdk := NewWithOptions("https://example.com/webhook", WithSecret("secret"))
+		if sdk.Client().Secret != "secret" {
+			t.Errorf("Secret = %v, want secret", sdk.Client().Secret)
+		}
+	})
+}
@@ -0,0 +1,506 @@
+package feishu
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	outboxSegmentExt = ".log"
+	outboxOpPut      = "put"
+	outboxOpAck      = "ack"
+	outboxOpDead     = "dead"
+
+	// 无法解码的记录原样追加到该文件，不参与重放
+	outboxQuarantineFile = "quarantine.jsonl"
+)
+
+var (
+	ErrOutboxClosed   = errors.New("feishu: outbox closed")
+	ErrOutboxNotFound = errors.New("feishu: outbox entry not found")
+)
+
+type OutboxOption func(*Outbox)
+
+// WithSegmentSize 设置单个段文件的大小上限，超过后压缩并切换到新段
+func WithSegmentSize(size int64) OutboxOption {
+	return func(o *Outbox) {
+		if size > 0 {
+			o.maxSegmentSize = size
+		}
+	}
+}
+
+// WithoutSync 关闭每次写入后的 fsync，提高吞吐但进程崩溃时可能丢失最近的消息
+func WithoutSync() OutboxOption {
+	return func(o *Outbox) {
+		o.sync = false
+	}
+}
+
+type outboxRecord struct {
+	Op      string          `json:"op"`
+	ID      uint64          `json:"id"`
+	Message json.RawMessage `json:"message,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// OutboxEntry 是发件箱中的一条记录，Err 为进入死信的原因
+type OutboxEntry struct {
+	ID      uint64
+	Message *Message
+	Err     string
+}
+
+// Outbox 在发送前把消息写入本地追加日志，发送成功后再记录确认，
+// 进程重启后可以通过 Replay 重新发送未确认的消息
+type Outbox struct {
+	dir            string
+	sender         Sender
+	maxSegmentSize int64
+	sync           bool
+
+	mu          sync.Mutex
+	replayMu    sync.Mutex
+	file        *os.File
+	segment     uint64
+	segmentSize int64
+	nextID      uint64
+	pending     map[uint64]*Message
+	dead        map[uint64]*OutboxEntry
+	closed      bool
+}
+
+func OpenOutbox(dir string, sender Sender, opts ...OutboxOption) (*Outbox, error) {
+	o := &Outbox{
+		dir:            dir,
+		sender:         sender,
+		maxSegmentSize: 4 << 20,
+		sync:           true,
+		nextID:         1,
+		pending:        make(map[uint64]*Message),
+		dead:           make(map[uint64]*OutboxEntry),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create outbox dir failed: %w", err)
+	}
+
+	segments, err := o.segments()
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		if err := o.load(segment); err != nil {
+			return nil, err
+		}
+		o.segment = segment
+	}
+
+	if err := o.compact(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// SendMessageContext 校验消息后持久化并发送，无效的消息不会写入发件箱
+func (o *Outbox) SendMessageContext(ctx context.Context, message *Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+	id, err := o.put(message)
+	if err != nil {
+		return err
+	}
+	return o.deliver(ctx, id, message)
+}
+
+func (o *Outbox) SendMessage(message *Message) error {
+	return o.SendMessageContext(context.Background(), message)
+}
+
+// Replay 按写入顺序重新发送未确认的消息。遇到可重试的错误时停止以保持顺序，
+// 不可重试的错误（参数错误、消息过大等）会把该消息移入死信并继续发送后面的消息
+func (o *Outbox) Replay(ctx context.Context) error {
+	o.replayMu.Lock()
+	defer o.replayMu.Unlock()
+
+	o.mu.Lock()
+	ids := o.pendingIDs()
+	messages := make([]*Message, len(ids))
+	for i, id := range ids {
+		messages[i] = o.pending[id]
+	}
+	o.mu.Unlock()
+
+	for i, id := range ids {
+		if err := o.deliver(ctx, id, messages[i]); err != nil {
+			if o.isDead(id) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) Pending() []*Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ids := o.pendingIDs()
+	messages := make([]*Message, len(ids))
+	for i, id := range ids {
+		messages[i] = o.pending[id]
+	}
+	return messages
+}
+
+// PendingEntries 按写入顺序返回未确认的消息及其 ID
+func (o *Outbox) PendingEntries() []OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ids := o.pendingIDs()
+	entries := make([]OutboxEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = OutboxEntry{ID: id, Message: o.pending[id]}
+	}
+	return entries
+}
+
+// DeadLetters 按写入顺序返回因不可重试的错误而放弃发送的消息
+func (o *Outbox) DeadLetters() []OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ids := make([]uint64, 0, len(o.dead))
+	for id := range o.dead {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	entries := make([]OutboxEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = *o.dead[id]
+	}
+	return entries
+}
+
+// Drop 删除一条未确认的消息或死信，供运维手动清理
+func (o *Outbox) Drop(id uint64) error {
+	o.mu.Lock()
+	_, pending := o.pending[id]
+	_, dead := o.dead[id]
+	o.mu.Unlock()
+
+	if !pending && !dead {
+		return ErrOutboxNotFound
+	}
+	return o.ack(id)
+}
+
+// Compact 将未确认的消息写入新段并删除旧段
+func (o *Outbox) Compact() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return ErrOutboxClosed
+	}
+	return o.compact()
+}
+
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	return o.file.Close()
+}
+
+func (o *Outbox) deliver(ctx context.Context, id uint64, message *Message) error {
+	if err := o.sender.SendMessageContext(ctx, message); err != nil {
+		if permanentOutboxError(err) {
+			if dlErr := o.deadLetter(id, err); dlErr != nil {
+				return errors.Join(err, dlErr)
+			}
+		}
+		return err
+	}
+	return o.ack(id)
+}
+
+// permanentOutboxError 只把消息本身导致的失败当作永久错误。签名、IP 白名单等配置错误修正后仍可重放，
+// 无法识别的错误（例如代理返回的非 JSON 页面）也保留等待重放
+func permanentOutboxError(err error) bool {
+	var validation ValidationErrors
+	return errors.As(err, &validation) || errors.Is(err, ErrMessageTooLarge) ||
+		errors.Is(err, ErrParamInvalid) || errors.Is(err, ErrKeywordMismatch)
+}
+
+func (o *Outbox) put(message *Message) (uint64, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return 0, fmt.Errorf("encode outbox message failed: %w", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return 0, ErrOutboxClosed
+	}
+
+	id := o.nextID
+	if err := o.append(&outboxRecord{Op: outboxOpPut, ID: id, Message: data}); err != nil {
+		return 0, err
+	}
+	o.nextID++
+	o.pending[id] = message
+	return id, nil
+}
+
+func (o *Outbox) ack(id uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, pending := o.pending[id]
+	_, dead := o.dead[id]
+	if !pending && !dead {
+		return nil
+	}
+	if o.closed {
+		return ErrOutboxClosed
+	}
+	if err := o.append(&outboxRecord{Op: outboxOpAck, ID: id}); err != nil {
+		return err
+	}
+	delete(o.pending, id)
+	delete(o.dead, id)
+
+	if o.segmentSize >= o.maxSegmentSize {
+		return o.compact()
+	}
+	return nil
+}
+
+func (o *Outbox) deadLetter(id uint64, cause error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	message, ok := o.pending[id]
+	if !ok {
+		return nil
+	}
+	if o.closed {
+		return ErrOutboxClosed
+	}
+	if err := o.append(&outboxRecord{Op: outboxOpDead, ID: id, Error: cause.Error()}); err != nil {
+		return err
+	}
+	delete(o.pending, id)
+	o.dead[id] = &OutboxEntry{ID: id, Message: message, Err: cause.Error()}
+	return nil
+}
+
+func (o *Outbox) isDead(id uint64) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, ok := o.dead[id]
+	return ok
+}
+
+func (o *Outbox) append(record *outboxRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode outbox record failed: %w", err)
+	}
+	data = append(data, '\n')
+
+	n, err := o.file.Write(data)
+	o.segmentSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("write outbox failed: %w", err)
+	}
+	if o.sync {
+		if err := o.file.Sync(); err != nil {
+			return fmt.Errorf("sync outbox failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) compact() error {
+	old := o.file
+	oldSegment := o.segment
+
+	o.segment++
+	file, err := os.OpenFile(o.segmentPath(o.segment), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		o.segment--
+		return fmt.Errorf("create outbox segment failed: %w", err)
+	}
+	o.file = file
+	o.segmentSize = 0
+
+	for _, entry := range o.entries() {
+		data, err := json.Marshal(entry.Message)
+		if err != nil {
+			return fmt.Errorf("encode outbox message failed: %w", err)
+		}
+		if err := o.append(&outboxRecord{Op: outboxOpPut, ID: entry.ID, Message: data}); err != nil {
+			return err
+		}
+		if entry.Err != "" {
+			if err := o.append(&outboxRecord{Op: outboxOpDead, ID: entry.ID, Error: entry.Err}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("sync outbox failed: %w", err)
+	}
+
+	if old != nil {
+		old.Close()
+	}
+	segments, err := o.segments()
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment <= oldSegment {
+			if err := os.Remove(o.segmentPath(segment)); err != nil {
+				return fmt.Errorf("remove outbox segment failed: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) load(segment uint64) error {
+	file, err := os.Open(o.segmentPath(segment))
+	if err != nil {
+		return fmt.Errorf("open outbox segment failed: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var record outboxRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// 崩溃时可能留下写了一半的记录，隔离后继续加载
+			if err := o.quarantine(scanner.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch record.Op {
+		case outboxOpPut:
+			var message Message
+			if err := json.Unmarshal(record.Message, &message); err != nil {
+				if err := o.quarantine(scanner.Bytes()); err != nil {
+					return err
+				}
+				continue
+			}
+			o.pending[record.ID] = &message
+		case outboxOpAck:
+			delete(o.pending, record.ID)
+			delete(o.dead, record.ID)
+		case outboxOpDead:
+			if message, ok := o.pending[record.ID]; ok {
+				delete(o.pending, record.ID)
+				o.dead[record.ID] = &OutboxEntry{ID: record.ID, Message: message, Err: record.Error}
+			}
+		}
+		if record.ID >= o.nextID {
+			o.nextID = record.ID + 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read outbox segment failed: %w", err)
+	}
+	return nil
+}
+
+// quarantine 保存无法解码的记录，避免压缩时被静默丢弃
+func (o *Outbox) quarantine(line []byte) error {
+	file, err := os.OpenFile(filepath.Join(o.dir, outboxQuarantineFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open outbox quarantine failed: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(append([]byte(nil), line...), '\n')); err != nil {
+		return fmt.Errorf("write outbox quarantine failed: %w", err)
+	}
+	return file.Sync()
+}
+
+func (o *Outbox) segments() ([]uint64, error) {
+	entries, err := os.ReadDir(o.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read outbox dir failed: %w", err)
+	}
+
+	var segments []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, outboxSegmentExt) {
+			continue
+		}
+		segment, err := strconv.ParseUint(strings.TrimSuffix(name, outboxSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+	return segments, nil
+}
+
+func (o *Outbox) segmentPath(segment uint64) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%020d%s", segment, outboxSegmentExt))
+}
+
+// entries 按 ID 顺序返回未确认的消息和死信
+func (o *Outbox) entries() []OutboxEntry {
+	entries := make([]OutboxEntry, 0, len(o.pending)+len(o.dead))
+	for id, message := range o.pending {
+		entries = append(entries, OutboxEntry{ID: id, Message: message})
+	}
+	for _, entry := range o.dead {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries
+}
+
+func (o *Outbox) pendingIDs() []uint64 {
+	ids := make([]uint64, 0, len(o.pending))
+	for id := range o.pending {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
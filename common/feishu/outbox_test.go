@@ -0,0 +1,356 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func readOutbox(t *testing.T, dir string) string {
+	t.Helper()
+	files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	var sb strings.Builder
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read outbox failed: %v", err)
+		}
+		sb.Write(data)
+	}
+	return sb.String()
+}
+
+// 可重试的错误，消息保持未确认
+var errUnavailable = &APIError{HTTPStatus: http.StatusServiceUnavailable}
+
+func TestOutbox(t *testing.T) {
+	t.Run("发送前先持久化", func(t *testing.T) {
+		dir := t.TempDir()
+		outbox, err := OpenOutbox(dir, senderFunc(func(ctx context.Context, message *Message) error {
+			if !strings.Contains(readOutbox(t, dir), "persist-first") {
+				t.Error("message should be persisted before sending")
+			}
+			return nil
+		}))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer outbox.Close()
+
+		if err := outbox.SendMessage(NewTextMessage("persist-first")); err != nil {
+			t.Errorf("SendMessage() error: %v", err)
+		}
+		if n := len(outbox.Pending()); n != 0 {
+			t.Errorf("pending = %d, want 0", n)
+		}
+	})
+
+	t.Run("重启后重放未确认消息", func(t *testing.T) {
+		dir := t.TempDir()
+		failing := senderFunc(func(ctx context.Context, message *Message) error {
+			return errUnavailable
+		})
+
+		outbox, err := OpenOutbox(dir, failing)
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		for _, text := range []string{"first", "second", "third"} {
+			if err := outbox.SendMessage(NewTextMessage(text)); err == nil {
+				t.Error("Expected send error")
+			}
+		}
+		outbox.Close()
+
+		var mu sync.Mutex
+		var received []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				MsgType string      `json:"msg_type"`
+				Content TextContent `json:"content"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			mu.Lock()
+			received = append(received, request.Content.Text)
+			mu.Unlock()
+			successHandler(w, r)
+		}))
+		defer server.Close()
+
+		reopened, err := OpenOutbox(dir, NewClient(server.URL, "secret"))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer reopened.Close()
+
+		if n := len(reopened.Pending()); n != 3 {
+			t.Fatalf("pending after restart = %d, want 3", n)
+		}
+		if err := reopened.Replay(context.Background()); err != nil {
+			t.Fatalf("Replay() error: %v", err)
+		}
+
+		mu.Lock()
+		if strings.Join(received, ",") != "first,second,third" {
+			t.Errorf("received = %v", received)
+		}
+		mu.Unlock()
+		if n := len(reopened.Pending()); n != 0 {
+			t.Errorf("pending after replay = %d, want 0", n)
+		}
+	})
+
+	t.Run("重放遇错停止", func(t *testing.T) {
+		dir := t.TempDir()
+		var calls int
+		outbox, err := OpenOutbox(dir, senderFunc(func(ctx context.Context, message *Message) error {
+			calls++
+			return errUnavailable
+		}))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer outbox.Close()
+
+		outbox.SendMessage(NewTextMessage("a"))
+		outbox.SendMessage(NewTextMessage("b"))
+		calls = 0
+		if err := outbox.Replay(context.Background()); err == nil {
+			t.Error("Replay() should return the send error")
+		}
+		if calls != 1 {
+			t.Errorf("Replay() calls = %d, want 1", calls)
+		}
+		if n := len(outbox.Pending()); n != 2 {
+			t.Errorf("pending = %d, want 2", n)
+		}
+	})
+
+	t.Run("压缩已确认的记录", func(t *testing.T) {
+		dir := t.TempDir()
+		fail := false
+		outbox, err := OpenOutbox(dir, senderFunc(func(ctx context.Context, message *Message) error {
+			if fail {
+				return errUnavailable
+			}
+			return nil
+		}), WithSegmentSize(512), WithoutSync())
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer outbox.Close()
+
+		for i := 0; i < 50; i++ {
+			outbox.SendMessage(NewTextMessage("delivered message"))
+		}
+		fail = true
+		outbox.SendMessage(NewTextMessage("undelivered"))
+
+		if err := outbox.Compact(); err != nil {
+			t.Fatalf("Compact() error: %v", err)
+		}
+		files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+		if len(files) != 1 {
+			t.Errorf("segments = %d, want 1", len(files))
+		}
+		content := readOutbox(t, dir)
+		if strings.Contains(content, "delivered message") {
+			t.Error("delivered messages should be compacted")
+		}
+		if !strings.Contains(content, "undelivered") {
+			t.Error("pending message should survive compaction")
+		}
+	})
+
+	t.Run("隔离写了一半的记录", func(t *testing.T) {
+		dir := t.TempDir()
+		outbox, err := OpenOutbox(dir, senderFunc(func(ctx context.Context, message *Message) error {
+			return errUnavailable
+		}))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		outbox.SendMessage(NewTextMessage("kept"))
+		outbox.Close()
+
+		files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+		f, _ := os.OpenFile(files[len(files)-1], os.O_APPEND|os.O_WRONLY, 0o644)
+		f.WriteString(`{"op":"put","id":9,"mess`)
+		f.Close()
+
+		reopened, err := OpenOutbox(dir, senderFunc(func(ctx context.Context, message *Message) error { return nil }))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer reopened.Close()
+		pending := reopened.Pending()
+		if len(pending) != 1 {
+			t.Fatalf("pending = %d, want 1", len(pending))
+		}
+		if text := textOf(pending[0]); text != "kept" {
+			t.Errorf("pending message = %#v, want typed text content", pending[0].Content)
+		}
+		quarantined, err := os.ReadFile(filepath.Join(dir, outboxQuarantineFile))
+		if err != nil || string(quarantined) != `{"op":"put","id":9,"mess`+"\n" {
+			t.Errorf("quarantine = %q, %v", quarantined, err)
+		}
+	})
+
+	t.Run("无效消息不写入发件箱", func(t *testing.T) {
+		dir := t.TempDir()
+		outbox, err := OpenOutbox(dir, senderFunc(func(ctx context.Context, message *Message) error { return nil }))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer outbox.Close()
+
+		var errs ValidationErrors
+		if err := outbox.SendMessage(NewTextMessage("   ")); !errors.As(err, &errs) {
+			t.Errorf("SendMessage() = %v, want ValidationErrors", err)
+		}
+		if n := len(outbox.Pending()); n != 0 {
+			t.Errorf("pending = %d, want 0", n)
+		}
+	})
+
+	t.Run("不可重试的错误移入死信", func(t *testing.T) {
+		dir := t.TempDir()
+		var mu sync.Mutex
+		var sent []string
+		down := true
+		sender := senderFunc(func(ctx context.Context, message *Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if textOf(message) == "bad" {
+				return &APIError{Code: 19001, Msg: "param invalid"}
+			}
+			if down {
+				return errUnavailable
+			}
+			sent = append(sent, textOf(message))
+			return nil
+		})
+		outbox, err := OpenOutbox(dir, sender)
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+
+		outbox.SendMessage(NewTextMessage("first"))
+		if err := outbox.SendMessage(NewTextMessage("bad")); err == nil {
+			t.Error("SendMessage() should return the permanent error")
+		}
+		outbox.SendMessage(NewTextMessage("good"))
+
+		mu.Lock()
+		down = false
+		mu.Unlock()
+		if err := outbox.Replay(context.Background()); err != nil {
+			t.Fatalf("Replay() error: %v", err)
+		}
+		if strings.Join(sent, ",") != "first,good" {
+			t.Errorf("sent = %v, want [first good]", sent)
+		}
+
+		dead := outbox.DeadLetters()
+		if len(dead) != 1 || textOf(dead[0].Message) != "bad" || !strings.Contains(dead[0].Err, "19001") {
+			t.Fatalf("dead letters = %+v", dead)
+		}
+		outbox.Close()
+
+		reopened, err := OpenOutbox(dir, sender)
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer reopened.Close()
+		dead = reopened.DeadLetters()
+		if len(dead) != 1 || len(reopened.Pending()) != 0 {
+			t.Fatalf("after reopen dead = %+v, pending = %d", dead, len(reopened.Pending()))
+		}
+		if err := reopened.Drop(dead[0].ID); err != nil {
+			t.Fatalf("Drop() error: %v", err)
+		}
+		if n := len(reopened.DeadLetters()); n != 0 {
+			t.Errorf("dead letters after Drop = %d, want 0", n)
+		}
+		if err := reopened.Drop(dead[0].ID); !errors.Is(err, ErrOutboxNotFound) {
+			t.Errorf("second Drop() = %v, want ErrOutboxNotFound", err)
+		}
+	})
+
+	t.Run("无法识别的错误保留待重放", func(t *testing.T) {
+		var mu sync.Mutex
+		var sent []string
+		down := true
+		outbox, err := OpenOutbox(t.TempDir(), senderFunc(func(ctx context.Context, message *Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if down {
+				return fmt.Errorf("parse response failed: %w", errors.New("invalid character '<'"))
+			}
+			sent = append(sent, textOf(message))
+			return nil
+		}))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer outbox.Close()
+
+		if err := outbox.SendMessage(NewTextMessage("portal")); err == nil {
+			t.Error("SendMessage() should return the send error")
+		}
+		if n := len(outbox.DeadLetters()); n != 0 {
+			t.Fatalf("dead letters = %d, want 0", n)
+		}
+
+		mu.Lock()
+		down = false
+		mu.Unlock()
+		if err := outbox.Replay(context.Background()); err != nil {
+			t.Fatalf("Replay() error: %v", err)
+		}
+		if strings.Join(sent, ",") != "portal" {
+			t.Errorf("sent = %v, want [portal]", sent)
+		}
+	})
+
+	t.Run("手动删除未确认消息", func(t *testing.T) {
+		outbox, err := OpenOutbox(t.TempDir(), senderFunc(func(ctx context.Context, message *Message) error {
+			return errUnavailable
+		}))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		defer outbox.Close()
+
+		outbox.SendMessage(NewTextMessage("stuck"))
+		entries := outbox.PendingEntries()
+		if len(entries) != 1 || textOf(entries[0].Message) != "stuck" {
+			t.Fatalf("pending entries = %+v", entries)
+		}
+		if err := outbox.Drop(entries[0].ID); err != nil {
+			t.Fatalf("Drop() error: %v", err)
+		}
+		if n := len(outbox.Pending()); n != 0 {
+			t.Errorf("pending = %d, want 0", n)
+		}
+	})
+
+	t.Run("关闭后拒绝发送", func(t *testing.T) {
+		outbox, err := OpenOutbox(t.TempDir(), senderFunc(func(ctx context.Context, message *Message) error { return nil }))
+		if err != nil {
+			t.Fatalf("OpenOutbox() error: %v", err)
+		}
+		outbox.Close()
+		if err := outbox.SendMessage(NewTextMessage("x")); !errors.Is(err, ErrOutboxClosed) {
+			t.Errorf("SendMessage() = %v, want ErrOutboxClosed", err)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package feishu
+
+import "fmt"
+
+type Locale string
+
+const (
+	LocaleZhCN Locale = "zh_cn"
+	LocaleEnUS Locale = "en_us"
+	LocaleJaJP Locale = "ja_jp"
+	LocaleZhHK Locale = "zh_hk"
+	LocaleZhTW Locale = "zh_tw"
+)
+
+// 富文本消息支持的语言，同时也是 Localized 的默认回退顺序
+var SupportedLocales = []Locale{LocaleZhCN, LocaleEnUS, LocaleJaJP, LocaleZhHK, LocaleZhTW}
+
+func (p *Post) field(locale Locale) **PostContent {
+	switch locale {
+	case LocaleZhCN:
+		return &p.ZhCn
+	case LocaleEnUS:
+		return &p.EnUs
+	case LocaleJaJP:
+		return &p.JaJp
+	case LocaleZhHK:
+		return &p.ZhHk
+	case LocaleZhTW:
+		return &p.ZhTw
+	}
+	return nil
+}
+
+func (p *Post) Get(locale Locale) *PostContent {
+	if f := p.field(locale); f != nil {
+		return *f
+	}
+	return nil
+}
+
+func (p *Post) Set(locale Locale, content *PostContent) error {
+	f := p.field(locale)
+	if f == nil {
+		return fmt.Errorf("unsupported locale %q", locale)
+	}
+	*f = content
+	return nil
+}
+
+// Locales 返回已设置内容的语言
+func (p *Post) Locales() []Locale {
+	var locales []Locale
+	for _, locale := range SupportedLocales {
+		if p.Get(locale) != nil {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// Localized 返回指定语言的内容，缺失时依次回退到 fallback 和 SupportedLocales 中第一个存在的语言
+func (p *Post) Localized(locale Locale, fallback ...Locale) *PostContent {
+	for _, l := range append(append([]Locale{locale}, fallback...), SupportedLocales...) {
+		if content := p.Get(l); content != nil {
+			return content
+		}
+	}
+	return nil
+}
+
+// PostBuilder 用于构建多语言富文本消息
+type PostBuilder struct {
+	post       *Post
+	fallback   Locale
+	fallbackTo []Locale
+	v          validator
+}
+
+func NewPostBuilder() *PostBuilder {
+	return &PostBuilder{post: &Post{}}
+}
+
+func (b *PostBuilder) Locale(locale Locale, title string, content [][]RichTextElement) *PostBuilder {
+	if err := b.post.Set(locale, &PostContent{Title: title, Content: content}); err != nil {
+		b.v.add(fmt.Sprintf("post.%s", locale), "unsupported locale")
+	}
+	return b
+}
+
+// Fallback 指定回退语言，Build 时用它的内容补齐 to 中未设置的语言。
+// 每种语言都会完整复制一份内容，只列出确实需要的语言以免超过消息大小限制；未列出的语言由飞书客户端自行回退
+func (b *PostBuilder) Fallback(locale Locale, to ...Locale) *PostBuilder {
+	b.fallback = locale
+	b.fallbackTo = to
+	return b
+}
+
+func (b *PostBuilder) Build() (*Message, error) {
+	v := &validator{errs: append(ValidationErrors(nil), b.v.errs...)}
+	post := *b.post
+
+	if len(post.Locales()) == 0 {
+		v.add("post", "at least one locale is required")
+	}
+	if b.fallback != "" {
+		fallback := post.Get(b.fallback)
+		if fallback == nil {
+			v.add("fallback", "locale %q has no content", b.fallback)
+		}
+		for _, locale := range b.fallbackTo {
+			if post.field(locale) == nil {
+				v.add(fmt.Sprintf("fallback.%s", locale), "unsupported locale")
+				continue
+			}
+			if post.Get(locale) == nil {
+				post.Set(locale, fallback)
+			}
+		}
+	}
+
+	post.validate(v, "post")
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return &Message{
+		MsgType: MessageTypeRichText,
+		Content: &RichTextContent{Post: &post},
+	}, nil
+}
@@ -0,0 +1,132 @@
+package feishu
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostLocales(t *testing.T) {
+	post := &Post{}
+	zh := &PostContent{Title: "标题"}
+	ja := &PostContent{Title: "タイトル"}
+
+	if err := post.Set(LocaleZhCN, zh); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := post.Set(LocaleJaJP, ja); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := post.Set("fr_fr", zh); err == nil {
+		t.Error("Set() should reject unsupported locales")
+	}
+
+	if got := post.Get(LocaleJaJP); got != ja {
+		t.Errorf("Get(ja_jp) = %v", got)
+	}
+	if got := post.Locales(); len(got) != 2 || got[0] != LocaleZhCN || got[1] != LocaleJaJP {
+		t.Errorf("Locales() = %v", got)
+	}
+
+	if got := post.Localized(LocaleEnUS); got != zh {
+		t.Errorf("Localized(en_us) should fall back to zh_cn, got %v", got)
+	}
+	if got := post.Localized(LocaleEnUS, LocaleJaJP); got != ja {
+		t.Errorf("Localized(en_us, ja_jp) should fall back to ja_jp, got %v", got)
+	}
+	if got := (&Post{}).Localized(LocaleEnUS); got != nil {
+		t.Errorf("empty post Localized() = %v, want nil", got)
+	}
+}
+
+func TestPostBuilder(t *testing.T) {
+	zhContent := [][]RichTextElement{{CreateRichTextElement("text", "你好")}}
+	enContent := [][]RichTextElement{{CreateRichTextElement("text", "Hello")}}
+	jaContent := [][]RichTextElement{{CreateRichTextElement("text", "こんにちは")}}
+
+	t.Run("多语言序列化", func(t *testing.T) {
+		msg, err := NewPostBuilder().
+			Locale(LocaleZhCN, "标题", zhContent).
+			Locale(LocaleEnUS, "Title", enContent).
+			Locale(LocaleJaJP, "タイトル", jaContent).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() error: %v", err)
+		}
+
+		assertJSON(t, msg, `{"msg_type":"post","content":{"post":{
+			"zh_cn":{"title":"标题","content":[[{"tag":"text","text":"你好"}]]},
+			"en_us":{"title":"Title","content":[[{"tag":"text","text":"Hello"}]]},
+			"ja_jp":{"title":"タイトル","content":[[{"tag":"text","text":"こんにちは"}]]}
+		}}}`)
+	})
+
+	t.Run("回退语言补齐", func(t *testing.T) {
+		msg, err := NewPostBuilder().
+			Locale(LocaleZhCN, "标题", zhContent).
+			Locale(LocaleEnUS, "Title", enContent).
+			Fallback(LocaleEnUS, LocaleZhCN, LocaleJaJP).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() error: %v", err)
+		}
+
+		post := msg.Content.(*RichTextContent).Post
+		if post.ZhCn.Title != "标题" {
+			t.Errorf("zh_cn should keep its own content, got %v", post.ZhCn.Title)
+		}
+		if got := post.JaJp; got == nil || got.Title != "Title" {
+			t.Errorf("ja_jp should fall back to en_us, got %v", got)
+		}
+		for _, locale := range []Locale{LocaleZhHK, LocaleZhTW} {
+			if got := post.Get(locale); got != nil {
+				t.Errorf("%s was not requested and should stay empty, got %v", locale, got)
+			}
+		}
+	})
+
+	t.Run("至少一种语言", func(t *testing.T) {
+		_, err := NewPostBuilder().Build()
+		var errs ValidationErrors
+		if !errors.As(err, &errs) || errs[0].Field != "post" {
+			t.Errorf("Build() = %v, want post validation error", err)
+		}
+	})
+
+	t.Run("不支持的语言", func(t *testing.T) {
+		if _, err := NewPostBuilder().Locale("fr_fr", "Titre", enContent).Locale(LocaleEnUS, "Title", enContent).Build(); err == nil {
+			t.Error("Build() should reject unsupported locales")
+		}
+	})
+
+	t.Run("回退语言不存在", func(t *testing.T) {
+		if _, err := NewPostBuilder().Locale(LocaleZhCN, "标题", zhContent).Fallback(LocaleEnUS).Build(); err == nil {
+			t.Error("Build() should reject a fallback without content")
+		}
+		if _, err := NewPostBuilder().Locale(LocaleZhCN, "标题", zhContent).Fallback(LocaleZhCN, "fr_fr").Build(); err == nil {
+			t.Error("Build() should reject an unsupported fallback target")
+		}
+	})
+
+	t.Run("SDK发送多语言富文本", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				Content RichTextContent `json:"content"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Content.Post == nil || request.Content.Post.JaJp == nil {
+				t.Error("ja_jp content should be sent")
+			}
+			successHandler(w, r)
+		}))
+		defer server.Close()
+
+		sdk := New(server.URL)
+		err := sdk.SendLocalizedRichText(NewPostBuilder().Locale(LocaleJaJP, "タイトル", jaContent))
+		if err != nil {
+			t.Errorf("SendLocalizedRichText() error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package feishu
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type RateLimitMode int
+
+const (
+	// RateLimitBlock 等待令牌，直到 ctx 结束
+	RateLimitBlock RateLimitMode = iota
+	// RateLimitReject 没有令牌时立即返回 ErrRateLimited
+	RateLimitReject
+)
+
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// 飞书自定义机器人的频率限制：每秒 5 次、每分钟 100 次
+var DefaultRateLimits = []RateLimit{
+	{Requests: 5, Per: time.Second},
+	{Requests: 100, Per: time.Minute},
+}
+
+// RateLimiter 由多个令牌桶组成，只有所有桶都有令牌时才放行
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets []*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	capacity float64
+	tokens   float64
+	interval time.Duration
+	last     time.Time
+}
+
+func NewRateLimiter(limits ...RateLimit) *RateLimiter {
+	if len(limits) == 0 {
+		limits = DefaultRateLimits
+	}
+
+	limiter := &RateLimiter{now: time.Now}
+	for _, limit := range limits {
+		if limit.Requests <= 0 || limit.Per <= 0 {
+			continue
+		}
+		limiter.buckets = append(limiter.buckets, &tokenBucket{
+			capacity: float64(limit.Requests),
+			tokens:   float64(limit.Requests),
+			interval: limit.Per / time.Duration(limit.Requests),
+		})
+	}
+	return limiter
+}
+
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.reserve() == 0
+}
+
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := l.reserve()
+		l.mu.Unlock()
+
+		if wait == 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve 在所有桶都有令牌时扣减并返回 0，否则返回需要等待的时间
+func (l *RateLimiter) reserve() time.Duration {
+	now := l.now()
+
+	var wait time.Duration
+	for _, b := range l.buckets {
+		b.refill(now)
+		if b.tokens < 1 {
+			if d := time.Duration((1 - b.tokens) * float64(b.interval)); d > wait {
+				wait = d
+			}
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	for _, b := range l.buckets {
+		b.tokens--
+	}
+	return 0
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.last.IsZero() {
+		b.last = now
+		return
+	}
+
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens += float64(elapsed) / float64(b.interval)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+var sharedLimiters = struct {
+	sync.Mutex
+	m map[string]*RateLimiter
+}{m: make(map[string]*RateLimiter)}
+
+// SharedRateLimiter 返回指定 webhook 共享的限流器，同一地址的多个 Client/SDK 共用配额
+func SharedRateLimiter(webhookURL string) *RateLimiter {
+	sharedLimiters.Lock()
+	defer sharedLimiters.Unlock()
+
+	limiter, ok := sharedLimiters.m[webhookURL]
+	if !ok {
+		limiter = NewRateLimiter(DefaultRateLimits...)
+		sharedLimiters.m[webhookURL] = limiter
+	}
+	return limiter
+}
+
+func (c *Client) acquire(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	if c.limitMode == RateLimitReject {
+		if !c.limiter.Allow() {
+			return ErrRateLimited
+		}
+		return nil
+	}
+	return c.limiter.Wait(ctx)
+}
@@ -0,0 +1,159 @@
+package feishu
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("令牌耗尽后拒绝", func(t *testing.T) {
+		now := time.Unix(1700000000, 0)
+		limiter := NewRateLimiter(RateLimit{Requests: 5, Per: time.Second}, RateLimit{Requests: 8, Per: time.Minute})
+		limiter.now = func() time.Time { return now }
+
+		for i := 0; i < 5; i++ {
+			if !limiter.Allow() {
+				t.Fatalf("request %d should be allowed", i)
+			}
+		}
+		if limiter.Allow() {
+			t.Error("6th request in the same second should be rejected")
+		}
+
+		now = now.Add(time.Second)
+		for i := 0; i < 3; i++ {
+			if !limiter.Allow() {
+				t.Fatalf("request %d after refill should be allowed", i)
+			}
+		}
+		if limiter.Allow() {
+			t.Error("per-minute bucket should be exhausted")
+		}
+	})
+
+	t.Run("按速率补充令牌", func(t *testing.T) {
+		now := time.Unix(1700000000, 0)
+		limiter := NewRateLimiter(RateLimit{Requests: 5, Per: time.Second})
+		limiter.now = func() time.Time { return now }
+
+		for limiter.Allow() {
+		}
+		now = now.Add(200 * time.Millisecond)
+		if !limiter.Allow() {
+			t.Error("one token should be refilled after 200ms")
+		}
+		if limiter.Allow() {
+			t.Error("only one token should be refilled")
+		}
+	})
+
+	t.Run("阻塞等待", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimit{Requests: 1, Per: 50 * time.Millisecond})
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Fatalf("Wait() error: %v", err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+			t.Errorf("Wait() returned too early: %v", elapsed)
+		}
+	})
+
+	t.Run("等待响应取消", func(t *testing.T) {
+		limiter := NewRateLimiter(RateLimit{Requests: 1, Per: time.Hour})
+		limiter.Allow()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Wait() error = %v, want DeadlineExceeded", err)
+		}
+	})
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	url := "https://example.com/shared-limiter"
+	if SharedRateLimiter(url) != SharedRateLimiter(url) {
+		t.Error("same webhook should share one limiter")
+	}
+	if SharedRateLimiter(url) == SharedRateLimiter(url+"-other") {
+		t.Error("different webhooks should not share a limiter")
+	}
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		successHandler(w, r)
+	}))
+	defer server.Close()
+
+	clientA := NewClientWithOptions(server.URL, WithRateLimit(RateLimitReject))
+	sdkB := NewWithOptions(server.URL, WithRateLimit(RateLimitReject))
+
+	var rejected int
+	for i := 0; i < 5; i++ {
+		for _, send := range []func(string) error{clientA.SendText, sdkB.SendText} {
+			err := send("test")
+			if errors.Is(err, ErrRateLimited) {
+				rejected++
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 5 {
+		t.Errorf("server calls = %d, want 5", got)
+	}
+	if rejected != 5 {
+		t.Errorf("rejected = %d, want 5", rejected)
+	}
+}
+
+func TestClientRateLimitBlock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(successHandler))
+	defer server.Close()
+
+	limiter := NewRateLimiter(RateLimit{Requests: 1, Per: time.Hour})
+	client := NewClientWithOptions(server.URL, WithRateLimiter(limiter, RateLimitBlock))
+
+	if err := client.SendText("first"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := client.SendTextContext(ctx, "second"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("blocked send should end with the context, got: %v", err)
+	}
+}
+
+func TestClientRateLimitRejectSkipsRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(successHandler))
+	defer server.Close()
+
+	limiter := NewRateLimiter(RateLimit{Requests: 1, Per: time.Hour})
+	client := NewClientWithOptions(server.URL,
+		WithRateLimiter(limiter, RateLimitReject),
+		WithRetryPolicy(&RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second}),
+	)
+
+	if err := client.SendText("first"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	if err := client.SendText("second"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("SendText() = %v, want ErrRateLimited", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("rejected send took %v, should fail fast", elapsed)
+	}
+}
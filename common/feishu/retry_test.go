@@ -0,0 +1,208 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newRetryTestServer(t *testing.T, responses []func(w http.ResponseWriter)) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1)) - 1
+		if n >= len(responses) {
+			n = len(responses) - 1
+		}
+		responses[n](w)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func respondStatus(status int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+		w.Write([]byte("error"))
+	}
+}
+
+func respondCode(code int, msg string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(200)
+		body, _ := json.Marshal(map[string]interface{}{"code": code, "msg": msg})
+		w.Write(body)
+	}
+}
+
+func fastRetryPolicy(attempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: attempts,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []func(w http.ResponseWriter)
+		wantCalls int32
+		wantErr   bool
+	}{
+		{
+			name:      "5xx后重试成功",
+			responses: []func(w http.ResponseWriter){respondStatus(500), respondStatus(502), respondCode(0, "success")},
+			wantCalls: 3,
+		},
+		{
+			name:      "频率限制后重试成功",
+			responses: []func(w http.ResponseWriter){respondCode(11232, "frequency limited"), respondCode(0, "success")},
+			wantCalls: 2,
+		},
+		{
+			name:      "参数错误不重试",
+			responses: []func(w http.ResponseWriter){respondCode(19001, "param invalid")},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "4xx不重试",
+			responses: []func(w http.ResponseWriter){respondStatus(400)},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "超过最大尝试次数",
+			responses: []func(w http.ResponseWriter){respondStatus(503)},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newRetryTestServer(t, tt.responses)
+			client := NewClient(server.URL).SetOptions(WithRetryPolicy(fastRetryPolicy(3)))
+
+			err := client.SendText("test")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+
+	t.Run("默认不重试", func(t *testing.T) {
+		server, calls := newRetryTestServer(t, []func(w http.ResponseWriter){respondStatus(500)})
+		client := NewClient(server.URL)
+
+		if err := client.SendText("test"); err == nil {
+			t.Error("Expected error")
+		}
+		if got := atomic.LoadInt32(calls); got != 1 {
+			t.Errorf("calls = %d, want 1", got)
+		}
+	})
+
+	t.Run("自定义重试判断", func(t *testing.T) {
+		server, calls := newRetryTestServer(t, []func(w http.ResponseWriter){respondCode(19001, "param invalid"), respondCode(0, "success")})
+		policy := fastRetryPolicy(2)
+		policy.RetryOn = func(err error) bool { return true }
+		client := NewClient(server.URL).SetOptions(WithRetryPolicy(policy))
+
+		if err := client.SendText("test"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if got := atomic.LoadInt32(calls); got != 2 {
+			t.Errorf("calls = %d, want 2", got)
+		}
+	})
+}
+
+func TestRetryResign(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request WebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		timestamp, err := strconv.ParseInt(request.Timestamp, 10, 64)
+		if err != nil {
+			t.Errorf("Invalid timestamp: %v", err)
+		}
+		want, _ := GenSign("retry-secret", timestamp)
+		if request.Sign != want {
+			t.Errorf("Sign = %v, want %v", request.Sign, want)
+		}
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(500)
+			return
+		}
+		w.WriteHeader(200)
+		w.Write([]byte(`{"code": 0, "msg": "success"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "retry-secret").SetOptions(WithRetryPolicy(fastRetryPolicy(2)))
+	if err := client.SendText("test"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	server, calls := newRetryTestServer(t, []func(w http.ResponseWriter){respondStatus(500)})
+	policy := &RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}
+	client := NewClient(server.URL).SetOptions(WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := client.SendTextContext(ctx, "test")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Error should wrap context.DeadlineExceeded, got: %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+		Jitter:      0.5,
+	}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := policy.backoff(attempt)
+		if delay < 0 || delay > policy.MaxDelay {
+			t.Errorf("backoff(%d) = %v, out of range", attempt, delay)
+		}
+	}
+
+	noJitter := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, w := range want {
+		if got := noJitter.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	var nilPolicy *RetryPolicy
+	if nilPolicy.attempts() != 1 {
+		t.Error("nil policy should attempt once")
+	}
+}
@@ -0,0 +1,162 @@
+package feishu
+
+import "fmt"
+
+const (
+	RichTextTagText      = "text"
+	RichTextTagLink      = "a"
+	RichTextTagAt        = "at"
+	RichTextTagImage     = "img"
+	RichTextTagMedia     = "media"
+	RichTextTagEmotion   = "emotion"
+	RichTextTagCodeBlock = "code_block"
+	RichTextTagHr        = "hr"
+	RichTextTagMarkdown  = "md"
+
+	// AtAll 作为 user_id 时表示 @所有人
+	AtAll = "all"
+)
+
+type TextStyle string
+
+const (
+	StyleBold        TextStyle = "bold"
+	StyleItalic      TextStyle = "italic"
+	StyleUnderline   TextStyle = "underline"
+	StyleLineThrough TextStyle = "lineThrough"
+)
+
+var richTextStyles = map[string]bool{
+	string(StyleBold):        true,
+	string(StyleItalic):      true,
+	string(StyleUnderline):   true,
+	string(StyleLineThrough): true,
+}
+
+func styleStrings(styles []TextStyle) []string {
+	if len(styles) == 0 {
+		return nil
+	}
+	result := make([]string, len(styles))
+	for i, style := range styles {
+		result[i] = string(style)
+	}
+	return result
+}
+
+func NewTextElement(text string, styles ...TextStyle) RichTextElement {
+	return RichTextElement{Tag: RichTextTagText, Text: text, Style: styleStrings(styles)}
+}
+
+// NewUnescapedTextElement 创建 un_escape 的文本元素，飞书会解析其中的 HTML 转义字符
+func NewUnescapedTextElement(text string, styles ...TextStyle) RichTextElement {
+	element := NewTextElement(text, styles...)
+	element.UnEscape = true
+	return element
+}
+
+func NewLinkElement(text, href string, styles ...TextStyle) RichTextElement {
+	return RichTextElement{Tag: RichTextTagLink, Text: text, Href: href, Style: styleStrings(styles)}
+}
+
+func NewAtElement(userID, userName string) RichTextElement {
+	return RichTextElement{Tag: RichTextTagAt, UserId: userID, UserName: userName}
+}
+
+func NewAtAllElement() RichTextElement {
+	return RichTextElement{Tag: RichTextTagAt, UserId: AtAll, UserName: "所有人"}
+}
+
+func NewImageElement(imageKey string) RichTextElement {
+	return RichTextElement{Tag: RichTextTagImage, ImageKey: imageKey}
+}
+
+// NewMediaElement 创建视频元素，imageKey 为视频封面，可以为空
+func NewMediaElement(fileKey, imageKey string) RichTextElement {
+	return RichTextElement{Tag: RichTextTagMedia, FileKey: fileKey, ImageKey: imageKey}
+}
+
+func NewEmotionElement(emojiType string) RichTextElement {
+	return RichTextElement{Tag: RichTextTagEmotion, EmojiType: emojiType}
+}
+
+func NewCodeBlockElement(language, code string) RichTextElement {
+	return RichTextElement{Tag: RichTextTagCodeBlock, Language: language, Text: code}
+}
+
+func NewHrElement() RichTextElement {
+	return RichTextElement{Tag: RichTextTagHr}
+}
+
+func NewMarkdownElement(text string) RichTextElement {
+	return RichTextElement{Tag: RichTextTagMarkdown, Text: text}
+}
+
+func (e RichTextElement) Validate() error {
+	v := &validator{}
+	e.validate(v, "")
+	return v.err()
+}
+
+func (e RichTextElement) validate(v *validator, path string) {
+	switch e.Tag {
+	case RichTextTagText, RichTextTagMarkdown, RichTextTagCodeBlock:
+		if e.Text == "" {
+			v.add(joinPath(path, "text"), "is empty")
+		}
+	case RichTextTagLink:
+		if e.Text == "" {
+			v.add(joinPath(path, "text"), "is empty")
+		}
+		if e.Href == "" {
+			v.add(joinPath(path, "href"), "is empty")
+		}
+	case RichTextTagAt:
+		if e.UserId == "" {
+			v.add(joinPath(path, "user_id"), "is empty")
+		}
+	case RichTextTagImage:
+		if e.ImageKey == "" {
+			v.add(joinPath(path, "image_key"), "is empty")
+		}
+	case RichTextTagMedia:
+		if e.FileKey == "" {
+			v.add(joinPath(path, "file_key"), "is empty")
+		}
+	case RichTextTagEmotion:
+		if e.EmojiType == "" {
+			v.add(joinPath(path, "emoji_type"), "is empty")
+		}
+	case RichTextTagHr:
+	default:
+		v.add(joinPath(path, "tag"), "unknown rich text tag %q", e.Tag)
+	}
+
+	for i, style := range e.Style {
+		if !richTextStyles[style] {
+			v.add(indexPath(path, "style", i), "unknown style %q", style)
+		}
+	}
+}
+
+func (p *PostContent) Validate() error {
+	v := &validator{}
+	p.validate(v, "")
+	return v.err()
+}
+
+func (p *PostContent) validate(v *validator, path string) {
+	for i, paragraph := range p.Content {
+		for j, element := range paragraph {
+			element.validate(v, joinPath(path, fmt.Sprintf("content[%d][%d]", i, j)))
+		}
+	}
+}
+
+func (p *Post) validate(v *validator, path string) {
+	for _, locale := range SupportedLocales {
+		if content := p.Get(locale); content != nil {
+			content.validate(v, joinPath(path, string(locale)))
+		}
+	}
+}
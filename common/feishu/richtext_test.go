@@ -0,0 +1,120 @@
+package feishu
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRichTextConstructors(t *testing.T) {
+	tests := []struct {
+		name    string
+		element RichTextElement
+		want    string
+	}{
+		{"文本", NewTextElement("hello"), `{"tag":"text","text":"hello"}`},
+		{"带样式文本", NewTextElement("hello", StyleBold, StyleLineThrough), `{"tag":"text","text":"hello","style":["bold","lineThrough"]}`},
+		{"不转义文本", NewUnescapedTextElement("&lt;b&gt;"), `{"tag":"text","text":"&lt;b&gt;","un_escape":true}`},
+		{"链接", NewLinkElement("飞书", "https://www.feishu.cn", StyleUnderline), `{"tag":"a","text":"飞书","href":"https://www.feishu.cn","style":["underline"]}`},
+		{"@用户", NewAtElement("ou_123", "张三"), `{"tag":"at","user_id":"ou_123","user_name":"张三"}`},
+		{"@所有人", NewAtAllElement(), `{"tag":"at","user_id":"all","user_name":"所有人"}`},
+		{"图片", NewImageElement("img_v2_xxx"), `{"tag":"img","image_key":"img_v2_xxx"}`},
+		{"视频", NewMediaElement("file_v2_xxx", "img_v2_cover"), `{"tag":"media","file_key":"file_v2_xxx","image_key":"img_v2_cover"}`},
+		{"表情", NewEmotionElement("SMILE"), `{"tag":"emotion","emoji_type":"SMILE"}`},
+		{"代码块", NewCodeBlockElement("go", "fmt.Println()"), `{"tag":"code_block","language":"go","text":"fmt.Println()"}`},
+		{"分割线", NewHrElement(), `{"tag":"hr"}`},
+		{"markdown", NewMarkdownElement("**bold**"), `{"tag":"md","text":"**bold**"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.element.Validate(); err != nil {
+				t.Errorf("Validate() error: %v", err)
+			}
+			assertJSON(t, tt.element, tt.want)
+		})
+	}
+}
+
+func TestCreateRichTextElementNewOptions(t *testing.T) {
+	element := CreateRichTextElement("code_block", "x := 1", map[string]string{"language": "go"})
+	if element.Language != "go" {
+		t.Errorf("Language = %v, want go", element.Language)
+	}
+	element = CreateRichTextElement("media", "", map[string]string{"file_key": "file_1"})
+	if element.FileKey != "file_1" {
+		t.Errorf("FileKey = %v, want file_1", element.FileKey)
+	}
+	element = CreateRichTextElement("emotion", "", map[string]string{"emoji_type": "OK"})
+	if element.EmojiType != "OK" {
+		t.Errorf("EmojiType = %v, want OK", element.EmojiType)
+	}
+}
+
+func TestRichTextValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		element RichTextElement
+		field   string
+	}{
+		{"未知标签", RichTextElement{Tag: "video", Text: "x"}, "tag"},
+		{"空文本", NewTextElement(""), "text"},
+		{"链接缺少地址", NewLinkElement("x", ""), "href"},
+		{"@缺少用户", NewAtElement("", "张三"), "user_id"},
+		{"图片缺少key", NewImageElement(""), "image_key"},
+		{"视频缺少文件", NewMediaElement("", "img"), "file_key"},
+		{"表情缺少类型", NewEmotionElement(""), "emoji_type"},
+		{"未知样式", NewTextElement("x", "blink"), "style[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs ValidationErrors
+			if err := tt.element.Validate(); !errors.As(err, &errs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			if errs[0].Field != tt.field {
+				t.Errorf("Field = %q, want %q", errs[0].Field, tt.field)
+			}
+		})
+	}
+
+	t.Run("段落路径", func(t *testing.T) {
+		content := &PostContent{Content: [][]RichTextElement{
+			{NewTextElement("ok")},
+			{NewTextElement("ok"), {Tag: "unknown"}},
+		}}
+		var errs ValidationErrors
+		if err := content.Validate(); !errors.As(err, &errs) {
+			t.Fatalf("Validate() = %v", err)
+		}
+		if errs[0].Field != "content[1][1].tag" {
+			t.Errorf("Field = %q", errs[0].Field)
+		}
+	})
+}
+
+func TestSendRichTextRejectsUnknownTags(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		successHandler(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	content := [][]RichTextElement{{CreateRichTextElement("blink", "x")}}
+	if err := client.SendRichText("title", content); err == nil {
+		t.Error("SendRichText() should reject unknown tags")
+	}
+
+	_, err := NewPostBuilder().Locale(LocaleZhCN, "标题", content).Build()
+	if err == nil {
+		t.Error("PostBuilder.Build() should reject unknown tags")
+	}
+
+	if calls != 0 {
+		t.Errorf("server calls = %d, want 0", calls)
+	}
+}
@@ -5,8 +5,36 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"strconv"
+	"time"
 )
 
+// 时间戳相关的错误都包装了 ErrSignatureInvalid，调用方只需判断 ErrSignatureInvalid
+var (
+	ErrInvalidTimestamp = fmt.Errorf("%w: invalid timestamp", ErrSignatureInvalid)
+	ErrTimestampSkew    = fmt.Errorf("%w: timestamp outside allowed skew", ErrSignatureInvalid)
+)
+
+// Clock 提供签名使用的当前时间，便于在测试中固定时间或校正已知的时钟偏差
+type Clock interface {
+	Now() time.Time
+}
+
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+var SystemClock Clock = ClockFunc(time.Now)
+
+// OffsetClock 返回比 base 快 offset 的时钟，offset 为负时表示更慢
+func OffsetClock(base Clock, offset time.Duration) Clock {
+	return ClockFunc(func() time.Time {
+		return base.Now().Add(offset)
+	})
+}
+
 func GenSign(secret string, timestamp int64) (string, error) {
 	stringToSign := fmt.Sprintf("%v", timestamp) + "\n" + secret
 	
@@ -14,3 +42,35 @@ func GenSign(secret string, timestamp int64) (string, error) {
 	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
 	return signature, nil
 }
+
+// VerifySign 校验飞书风格的签名，maxSkew 大于 0 时还会检查时间戳与当前时间的偏差。
+// 校验失败时返回的错误都满足 errors.Is(err, ErrSignatureInvalid)
+func VerifySign(secret, timestamp, sign string, maxSkew time.Duration) error {
+	return verifySign(SystemClock, secret, timestamp, sign, maxSkew)
+}
+
+func verifySign(clock Clock, secret, timestamp, sign string, maxSkew time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidTimestamp, timestamp)
+	}
+
+	if maxSkew > 0 {
+		skew := clock.Now().Sub(time.Unix(ts, 0))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > maxSkew {
+			return fmt.Errorf("%w: %s > %s", ErrTimestampSkew, skew, maxSkew)
+		}
+	}
+
+	expected, err := GenSign(secret, ts)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(sign)) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
@@ -1,6 +1,11 @@
 package feishu
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -113,3 +118,94 @@ func TestSignatureValidation(t *testing.T) {
 		t.Error("Different secrets should produce different signatures")
 	}
 }
+
+func fixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+func TestVerifySign(t *testing.T) {
+	const secret = "verify-secret"
+	now := time.Unix(1700000000, 0)
+	clock := fixedClock(now)
+	sign, _ := GenSign(secret, now.Unix())
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		sign      string
+		maxSkew   time.Duration
+		want      error
+	}{
+		{"签名正确", secret, timestamp, sign, time.Minute, nil},
+		{"签名错误", secret, timestamp, sign[:len(sign)-2] + "AA", time.Minute, ErrSignatureInvalid},
+		{"密钥错误", "other-secret", timestamp, sign, time.Minute, ErrSignatureInvalid},
+		{"时间戳格式错误", secret, "abc", sign, time.Minute, ErrInvalidTimestamp},
+		{"时间戳过旧", secret, strconv.FormatInt(now.Add(-2*time.Minute).Unix(), 10), sign, time.Minute, ErrTimestampSkew},
+		{"时间戳超前", secret, strconv.FormatInt(now.Add(2*time.Minute).Unix(), 10), sign, time.Minute, ErrTimestampSkew},
+		{"不检查偏差", secret, timestamp, sign, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySign(clock, tt.secret, tt.timestamp, tt.sign, tt.maxSkew)
+			if tt.want == nil && err != nil {
+				t.Errorf("verifySign() = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("verifySign() = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil && !errors.Is(err, ErrSignatureInvalid) {
+				t.Errorf("verifySign() = %v, should wrap ErrSignatureInvalid", err)
+			}
+		})
+	}
+
+	t.Run("使用系统时钟", func(t *testing.T) {
+		ts := time.Now().Unix()
+		sign, _ := GenSign(secret, ts)
+		if err := VerifySign(secret, strconv.FormatInt(ts, 10), sign, time.Minute); err != nil {
+			t.Errorf("VerifySign() = %v", err)
+		}
+	})
+}
+
+func TestClientClock(t *testing.T) {
+	var request WebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&request)
+		successHandler(w, r)
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+
+	t.Run("注入时钟", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL, WithSecret("secret"), WithClock(fixedClock(now)))
+		if err := client.SendText("hello"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if request.Timestamp != "1700000000" {
+			t.Errorf("Timestamp = %v, want 1700000000", request.Timestamp)
+		}
+		if err := verifySign(fixedClock(now), "secret", request.Timestamp, request.Sign, time.Second); err != nil {
+			t.Errorf("verifySign() = %v", err)
+		}
+	})
+
+	t.Run("校正时钟偏差", func(t *testing.T) {
+		for _, opts := range [][]ClientOption{
+			{WithSecret("secret"), WithClock(fixedClock(now)), WithClockOffset(-30 * time.Second)},
+			{WithSecret("secret"), WithClockOffset(-30 * time.Second), WithClock(fixedClock(now))},
+		} {
+			client := NewClientWithOptions(server.URL, opts...)
+			if err := client.SendText("hello"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if request.Timestamp != "1699999970" {
+				t.Errorf("Timestamp = %v, want 1699999970", request.Timestamp)
+			}
+		}
+	})
+}
@@ -0,0 +1,381 @@
+package feishu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 飞书自定义机器人请求体不能超过 20KB
+const DefaultMaxBodySize = 20 * 1024
+
+const DefaultTruncateMarker = "\n...(truncated)"
+
+type OversizeMode int
+
+const (
+	// OversizeStrict 超过大小限制时返回 ErrMessageTooLarge
+	OversizeStrict OversizeMode = iota
+	// OversizeSplit 将文本和富文本按行或段落拆分为带编号的多条消息
+	OversizeSplit
+	// OversizeTruncate 截断文本和富文本，并在末尾追加截断标记
+	OversizeTruncate
+)
+
+// 签名的占位值，长度与真实的时间戳和 base64 编码的 HMAC-SHA256 一致
+var (
+	placeholderTimestamp = strings.Repeat("0", 10)
+	placeholderSign      = strings.Repeat("0", 44)
+)
+
+func (c *Client) requestSize(message *Message) (int, error) {
+	request := &WebhookRequest{
+		MsgType: string(message.MsgType),
+		Content: message.Content,
+	}
+	if c.Secret != "" {
+		request.Timestamp = placeholderTimestamp
+		request.Sign = placeholderSign
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return 0, fmt.Errorf("encode request failed: %w", err)
+	}
+	return len(data), nil
+}
+
+// fitMessage 检查消息序列化后的大小，并按 OversizeMode 返回需要依次发送的消息
+func (c *Client) fitMessage(message *Message) ([]*Message, error) {
+	if c.maxBodySize <= 0 {
+		return []*Message{message}, nil
+	}
+
+	size, err := c.requestSize(message)
+	if err != nil {
+		return nil, err
+	}
+	if size <= c.maxBodySize {
+		return []*Message{message}, nil
+	}
+
+	tooLarge := fmt.Errorf("%w: request body is %d bytes, limit is %d", ErrMessageTooLarge, size, c.maxBodySize)
+	if c.oversize == OversizeStrict {
+		return nil, tooLarge
+	}
+
+	var messages []*Message
+	switch content := message.Content.(type) {
+	case *TextContent:
+		if c.oversize == OversizeSplit {
+			messages, err = c.splitText(content.Text)
+		} else {
+			messages, err = c.truncateText(content.Text)
+		}
+	case *RichTextContent:
+		if content.Post == nil {
+			return nil, tooLarge
+		}
+		if c.oversize == OversizeSplit {
+			messages, err = c.splitPost(content.Post)
+		} else {
+			messages, err = c.truncatePost(content.Post)
+		}
+	default:
+		return nil, tooLarge
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMessageTooLarge, err)
+	}
+	return messages, nil
+}
+
+func (c *Client) splitText(text string) ([]*Message, error) {
+	overhead, err := c.requestSize(NewTextMessage(""))
+	if err != nil {
+		return nil, err
+	}
+
+	for digits := 1; ; digits++ {
+		nines := strings.Repeat("9", digits)
+		budget := c.maxBodySize - overhead - len(fmt.Sprintf("(%s/%s) ", nines, nines))
+		if budget <= 0 {
+			return nil, fmt.Errorf("no room left for content")
+		}
+
+		chunks, err := splitTextChunks(text, budget)
+		if err != nil {
+			return nil, err
+		}
+		if len(strconv.Itoa(len(chunks))) > digits {
+			continue
+		}
+
+		messages := make([]*Message, len(chunks))
+		for i, chunk := range chunks {
+			messages[i] = NewTextMessage(fmt.Sprintf("(%d/%d) %s", i+1, len(chunks), chunk))
+		}
+		return messages, nil
+	}
+}
+
+func (c *Client) truncateText(text string) ([]*Message, error) {
+	overhead, err := c.requestSize(NewTextMessage(""))
+	if err != nil {
+		return nil, err
+	}
+
+	budget := c.maxBodySize - overhead - escapedLen(c.truncateMarker)
+	if budget <= 0 {
+		return nil, fmt.Errorf("no room left for content")
+	}
+	head, _ := cutEscaped(text, budget)
+	return []*Message{NewTextMessage(head + c.truncateMarker)}, nil
+}
+
+func (c *Client) splitPost(post *Post) ([]*Message, error) {
+	locales := post.Locales()
+	if len(locales) == 0 {
+		return nil, fmt.Errorf("post has no content")
+	}
+
+	for digits := 1; ; digits++ {
+		nines := strings.Repeat("9", digits)
+		suffix := fmt.Sprintf(" (%s/%s)", nines, nines)
+		budget, err := c.postRowBudget(post, locales, suffix)
+		if err != nil {
+			return nil, err
+		}
+
+		chunks := make(map[Locale][][][]RichTextElement, len(locales))
+		parts := 0
+		for _, locale := range locales {
+			rows, err := splitRows(post.Get(locale).Content, budget)
+			if err != nil {
+				return nil, err
+			}
+			chunks[locale] = packRows(rows, budget)
+			if len(chunks[locale]) > parts {
+				parts = len(chunks[locale])
+			}
+		}
+		if len(strconv.Itoa(parts)) > digits {
+			continue
+		}
+
+		messages := make([]*Message, parts)
+		for i := range messages {
+			part := &Post{}
+			for _, locale := range locales {
+				content := [][]RichTextElement{}
+				if i < len(chunks[locale]) {
+					content = chunks[locale][i]
+				}
+				part.Set(locale, &PostContent{
+					Title:   fmt.Sprintf("%s (%d/%d)", post.Get(locale).Title, i+1, parts),
+					Content: content,
+				})
+			}
+			messages[i] = &Message{MsgType: MessageTypeRichText, Content: &RichTextContent{Post: part}}
+		}
+		return messages, nil
+	}
+}
+
+func (c *Client) truncatePost(post *Post) ([]*Message, error) {
+	locales := post.Locales()
+	if len(locales) == 0 {
+		return nil, fmt.Errorf("post has no content")
+	}
+
+	markerRow := []RichTextElement{NewTextElement(strings.TrimSpace(c.truncateMarker))}
+	budget, err := c.postRowBudget(post, locales, "")
+	if err != nil {
+		return nil, err
+	}
+	budget -= jsonSize(markerRow) + 1
+	if budget <= 0 {
+		return nil, fmt.Errorf("no room left for content")
+	}
+
+	truncated := &Post{}
+	for _, locale := range locales {
+		rows, err := splitRows(post.Get(locale).Content, budget)
+		if err != nil {
+			return nil, err
+		}
+		kept := append(packRows(rows, budget)[0], markerRow)
+		truncated.Set(locale, &PostContent{Title: post.Get(locale).Title, Content: kept})
+	}
+	return []*Message{{MsgType: MessageTypeRichText, Content: &RichTextContent{Post: truncated}}}, nil
+}
+
+// postRowBudget 返回每种语言的段落可用的字节数，标题会追加 suffix
+func (c *Client) postRowBudget(post *Post, locales []Locale, suffix string) (int, error) {
+	empty := &Post{}
+	for _, locale := range locales {
+		empty.Set(locale, &PostContent{Title: post.Get(locale).Title + suffix, Content: [][]RichTextElement{}})
+	}
+
+	overhead, err := c.requestSize(&Message{MsgType: MessageTypeRichText, Content: &RichTextContent{Post: empty}})
+	if err != nil {
+		return 0, err
+	}
+
+	budget := (c.maxBodySize - overhead) / len(locales)
+	if budget <= 2 {
+		return 0, fmt.Errorf("no room left for content")
+	}
+	return budget, nil
+}
+
+// splitRows 将超过 budget 的段落拆成多行，单个元素过长时按行拆分其文本
+func splitRows(rows [][]RichTextElement, budget int) ([][]RichTextElement, error) {
+	var result [][]RichTextElement
+	for _, row := range rows {
+		if jsonSize(row)+1 <= budget {
+			result = append(result, row)
+			continue
+		}
+
+		var current []RichTextElement
+		currentSize := 3
+		for _, element := range row {
+			size := jsonSize(element) + 1
+			if currentSize+size <= budget {
+				current = append(current, element)
+				currentSize += size
+				continue
+			}
+			if len(current) > 0 {
+				result = append(result, current)
+				current, currentSize = nil, 3
+			}
+			if 3+size <= budget {
+				current = append(current, element)
+				currentSize += size
+				continue
+			}
+
+			if element.Text == "" {
+				return nil, fmt.Errorf("%s element cannot be split", element.Tag)
+			}
+			template := element
+			template.Text = ""
+			textBudget := budget - 3 - jsonSize(template) - 1 - len(`"text":"",`)
+			if textBudget <= 0 {
+				return nil, fmt.Errorf("no room left for %s element", element.Tag)
+			}
+			textChunks, err := splitTextChunks(element.Text, textBudget)
+			if err != nil {
+				return nil, err
+			}
+			for _, chunk := range textChunks {
+				piece := element
+				piece.Text = chunk
+				result = append(result, []RichTextElement{piece})
+			}
+		}
+		if len(current) > 0 {
+			result = append(result, current)
+		}
+	}
+	return result, nil
+}
+
+func packRows(rows [][]RichTextElement, budget int) [][][]RichTextElement {
+	var chunks [][][]RichTextElement
+	var current [][]RichTextElement
+	size := 0
+	for _, row := range rows {
+		rowSize := jsonSize(row) + 1
+		if size+rowSize > budget && len(current) > 0 {
+			chunks = append(chunks, current)
+			current, size = nil, 0
+		}
+		current = append(current, row)
+		size += rowSize
+	}
+	if len(current) > 0 || len(chunks) == 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// splitTextChunks 按行拆分文本，使每段 JSON 转义后的长度不超过 budget；单行过长时按字符拆分，
+// budget 放不下一个字符时返回错误
+func splitTextChunks(text string, budget int) ([]string, error) {
+	var chunks []string
+	var current strings.Builder
+	size := 0
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, strings.TrimSuffix(current.String(), "\n"))
+			current.Reset()
+			size = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineSize := escapedLen(line)
+		if size+lineSize > budget {
+			flush()
+		}
+		for lineSize > budget {
+			head, rest := cutEscaped(line, budget)
+			if head == "" {
+				return nil, fmt.Errorf("no room left for a single character")
+			}
+			chunks = append(chunks, head)
+			line, lineSize = rest, escapedLen(rest)
+		}
+		current.WriteString(line)
+		size += lineSize
+	}
+	flush()
+
+	if len(chunks) == 0 {
+		chunks = append(chunks, "")
+	}
+	return chunks, nil
+}
+
+// cutEscaped 在字符边界处截断文本，使前半部分 JSON 转义后的长度不超过 budget
+func cutEscaped(s string, budget int) (string, string) {
+	size := 0
+	for i, r := range s {
+		size += escapedRuneLen(r)
+		if size > budget {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+func escapedLen(s string) int {
+	size := 0
+	for _, r := range s {
+		size += escapedRuneLen(r)
+	}
+	return size
+}
+
+// escapedRuneLen 与 encoding/json 的字符串转义规则保持一致
+func escapedRuneLen(r rune) int {
+	switch {
+	case r == '"' || r == '\\' || r == '\n' || r == '\r' || r == '\t':
+		return 2
+	case r < 0x20 || r == '<' || r == '>' || r == '&' || r == '\u2028' || r == '\u2029' || r == utf8.RuneError:
+		return 6
+	}
+	return utf8.RuneLen(r)
+}
+
+func jsonSize(v interface{}) int {
+	data, _ := json.Marshal(v)
+	return len(data)
+}
@@ -0,0 +1,338 @@
+package feishu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedBody struct {
+	MsgType string          `json:"msg_type"`
+	Content json.RawMessage `json:"content"`
+	size    int
+}
+
+func newRecordingServer(t *testing.T) (*httptest.Server, func() []recordedBody) {
+	var mu sync.Mutex
+	var bodies []recordedBody
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body recordedBody
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("decode request failed: %v", err)
+		}
+		body.size = len(data)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		successHandler(w, r)
+	}))
+	return server, func() []recordedBody {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedBody(nil), bodies...)
+	}
+}
+
+func longText(lines int) string {
+	var sb strings.Builder
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(&sb, "第 %03d 行：<日志> \"内容\" & 更多内容\n", i)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func TestOversizeStrict(t *testing.T) {
+	server, bodies := newRecordingServer(t)
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL, WithMaxBodySize(1024))
+	err := client.SendText(longText(100))
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("err = %v, want ErrMessageTooLarge", err)
+	}
+	if len(bodies()) != 0 {
+		t.Errorf("requests = %d, want 0", len(bodies()))
+	}
+
+	t.Run("未超过限制时正常发送", func(t *testing.T) {
+		if err := client.SendText("short"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("默认限制为 20KB", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL)
+		err := client.SendText(strings.Repeat("a", DefaultMaxBodySize))
+		if !errors.Is(err, ErrMessageTooLarge) {
+			t.Errorf("err = %v, want ErrMessageTooLarge", err)
+		}
+	})
+
+	t.Run("关闭大小检查", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL, WithMaxBodySize(0))
+		if err := client.SendText(strings.Repeat("a", DefaultMaxBodySize)); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("签名开销计入大小", func(t *testing.T) {
+		text := strings.Repeat("a", 1024-len(`{"msg_type":"text","content":{"text":""}}`))
+		if err := NewClientWithOptions(server.URL, WithMaxBodySize(1024)).SendText(text); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		err := NewClientWithOptions(server.URL, WithMaxBodySize(1024), WithSecret("secret")).SendText(text)
+		if !errors.Is(err, ErrMessageTooLarge) {
+			t.Errorf("err = %v, want ErrMessageTooLarge", err)
+		}
+	})
+
+	t.Run("放不下一个转义字符", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL, WithMaxBodySize(48), WithOversizeMode(OversizeSplit))
+		done := make(chan error, 1)
+		go func() { done <- client.SendText(strings.Repeat("<", 50)) }()
+		select {
+		case err := <-done:
+			if !errors.Is(err, ErrMessageTooLarge) {
+				t.Errorf("err = %v, want ErrMessageTooLarge", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("SendText() did not return")
+		}
+
+		go func() {
+			// 覆盖富文本元素只剩几个字节预算的情况
+			for size := 64; size < 192; size++ {
+				client := NewClientWithOptions(server.URL, WithMaxBodySize(size), WithOversizeMode(OversizeSplit))
+				client.SendRichText("", [][]RichTextElement{{NewTextElement(strings.Repeat("<", 50))}})
+			}
+			done <- nil
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("SendRichText() did not return")
+		}
+	})
+
+	t.Run("不支持拆分的消息类型", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL, WithMaxBodySize(64), WithOversizeMode(OversizeSplit))
+		err := client.SendImage(strings.Repeat("k", 100))
+		if !errors.Is(err, ErrMessageTooLarge) {
+			t.Errorf("err = %v, want ErrMessageTooLarge", err)
+		}
+	})
+}
+
+func TestOversizeSplitText(t *testing.T) {
+	server, bodies := newRecordingServer(t)
+	defer server.Close()
+
+	const limit = 1024
+	text := longText(100)
+	client := NewClientWithOptions(server.URL, WithMaxBodySize(limit), WithOversizeMode(OversizeSplit), WithSecret("secret"))
+	if err := client.SendText(text); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got := bodies()
+	if len(got) < 2 {
+		t.Fatalf("requests = %d, want at least 2", len(got))
+	}
+
+	var lines []string
+	for i, body := range got {
+		if body.size > limit {
+			t.Errorf("part %d size = %d, limit %d", i+1, body.size, limit)
+		}
+		var content TextContent
+		json.Unmarshal(body.Content, &content)
+		prefix := fmt.Sprintf("(%d/%d) ", i+1, len(got))
+		if !strings.HasPrefix(content.Text, prefix) {
+			t.Fatalf("part %d = %q, want prefix %q", i+1, content.Text, prefix)
+		}
+		lines = append(lines, strings.TrimPrefix(content.Text, prefix))
+	}
+	if joined := strings.Join(lines, "\n"); joined != text {
+		t.Errorf("joined parts differ from original text")
+	}
+
+	t.Run("超长单行按字符拆分", func(t *testing.T) {
+		server, bodies := newRecordingServer(t)
+		defer server.Close()
+
+		text := strings.Repeat("飞书", 1000)
+		client := NewClientWithOptions(server.URL, WithMaxBodySize(limit), WithOversizeMode(OversizeSplit))
+		if err := client.SendText(text); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var sb strings.Builder
+		for i, body := range bodies() {
+			if body.size > limit {
+				t.Errorf("part %d size = %d, limit %d", i+1, body.size, limit)
+			}
+			var content TextContent
+			json.Unmarshal(body.Content, &content)
+			sb.WriteString(content.Text[strings.Index(content.Text, " ")+1:])
+		}
+		if sb.String() != text {
+			t.Error("joined parts differ from original text")
+		}
+	})
+}
+
+func TestOversizeSplitPost(t *testing.T) {
+	server, bodies := newRecordingServer(t)
+	defer server.Close()
+
+	const limit = 2048
+	var rows [][]RichTextElement
+	for i := 0; i < 60; i++ {
+		rows = append(rows, []RichTextElement{NewTextElement(fmt.Sprintf("第 %02d 段：", i)), NewLinkElement("链接", "https://example.com")})
+	}
+	rows = append(rows, []RichTextElement{NewCodeBlockElement("go", longText(40))})
+
+	client := NewClientWithOptions(server.URL, WithMaxBodySize(limit), WithOversizeMode(OversizeSplit))
+	if err := client.SendRichText("报告", rows); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got := bodies()
+	if len(got) < 2 {
+		t.Fatalf("requests = %d, want at least 2", len(got))
+	}
+
+	paragraphs := 0
+	var code []string
+	for i, body := range got {
+		if body.size > limit {
+			t.Errorf("part %d size = %d, limit %d", i+1, body.size, limit)
+		}
+		var content RichTextContent
+		json.Unmarshal(body.Content, &content)
+		zh := content.Post.ZhCn
+		if want := fmt.Sprintf("报告 (%d/%d)", i+1, len(got)); zh.Title != want {
+			t.Errorf("part %d title = %q, want %q", i+1, zh.Title, want)
+		}
+		for _, row := range zh.Content {
+			if row[0].Tag == RichTextTagCodeBlock {
+				code = append(code, row[0].Text)
+				if row[0].Language != "go" {
+					t.Errorf("code block language = %q", row[0].Language)
+				}
+				continue
+			}
+			paragraphs++
+		}
+	}
+	if paragraphs != 60 {
+		t.Errorf("paragraphs = %d, want 60", paragraphs)
+	}
+	if strings.Join(code, "\n") != longText(40) {
+		t.Error("code block content differs after split")
+	}
+
+	t.Run("多语言按语言分别拆分", func(t *testing.T) {
+		server, bodies := newRecordingServer(t)
+		defer server.Close()
+
+		builder := NewPostBuilder().
+			Locale(LocaleZhCN, "报告", rows[:60]).
+			Locale(LocaleEnUS, "Report", rows[:10])
+
+		client := NewClientWithOptions(server.URL, WithMaxBodySize(limit), WithOversizeMode(OversizeSplit))
+		if err := client.SendLocalizedRichText(builder); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i, body := range bodies() {
+			if body.size > limit {
+				t.Errorf("part %d size = %d, limit %d", i+1, body.size, limit)
+			}
+			var content RichTextContent
+			json.Unmarshal(body.Content, &content)
+			if content.Post.EnUs == nil || !strings.HasPrefix(content.Post.EnUs.Title, "Report (") {
+				t.Errorf("part %d missing en_us title", i+1)
+			}
+		}
+	})
+}
+
+func TestOversizeTruncate(t *testing.T) {
+	server, bodies := newRecordingServer(t)
+	defer server.Close()
+
+	const limit = 1024
+	client := NewClientWithOptions(server.URL, WithMaxBodySize(limit), WithOversizeMode(OversizeTruncate))
+	text := longText(100)
+	if err := client.SendText(text); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got := bodies()
+	if len(got) != 1 {
+		t.Fatalf("requests = %d, want 1", len(got))
+	}
+	if got[0].size > limit {
+		t.Errorf("size = %d, limit %d", got[0].size, limit)
+	}
+	var content TextContent
+	json.Unmarshal(got[0].Content, &content)
+	if !strings.HasSuffix(content.Text, DefaultTruncateMarker) {
+		t.Errorf("text = %q, want marker suffix", content.Text)
+	}
+	if !strings.HasPrefix(text, strings.TrimSuffix(content.Text, DefaultTruncateMarker)) {
+		t.Error("truncated text is not a prefix of the original")
+	}
+
+	t.Run("自定义截断标记", func(t *testing.T) {
+		server, bodies := newRecordingServer(t)
+		defer server.Close()
+
+		client := NewClientWithOptions(server.URL, WithMaxBodySize(limit), WithOversizeMode(OversizeTruncate), WithTruncateMarker("…[已截断]"))
+		if err := client.SendText(text); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var content TextContent
+		json.Unmarshal(bodies()[0].Content, &content)
+		if !strings.HasSuffix(content.Text, "…[已截断]") {
+			t.Errorf("text = %q, want custom marker", content.Text)
+		}
+	})
+
+	t.Run("富文本截断", func(t *testing.T) {
+		server, bodies := newRecordingServer(t)
+		defer server.Close()
+
+		var rows [][]RichTextElement
+		for i := 0; i < 100; i++ {
+			rows = append(rows, []RichTextElement{NewTextElement(fmt.Sprintf("第 %02d 段", i))})
+		}
+		client := NewClientWithOptions(server.URL, WithMaxBodySize(limit), WithOversizeMode(OversizeTruncate))
+		if err := client.SendRichText("报告", rows); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		got := bodies()
+		if got[0].size > limit {
+			t.Errorf("size = %d, limit %d", got[0].size, limit)
+		}
+		var content RichTextContent
+		json.Unmarshal(got[0].Content, &content)
+		zh := content.Post.ZhCn
+		last := zh.Content[len(zh.Content)-1]
+		if zh.Title != "报告" || last[0].Text != strings.TrimSpace(DefaultTruncateMarker) {
+			t.Errorf("title = %q, last row = %+v", zh.Title, last)
+		}
+		if len(zh.Content) < 2 || len(zh.Content) > 100 {
+			t.Errorf("rows = %d", len(zh.Content))
+		}
+	})
+}
@@ -0,0 +1,358 @@
+package feishu
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SlogFormat int
+
+const (
+	// SlogText 发送纯文本消息
+	SlogText SlogFormat = iota
+	// SlogCard 发送卡片，属性渲染为字段
+	SlogCard
+)
+
+const DefaultSlogDedupWindow = time.Minute
+
+// 过期但有重复次数未报告的去重记录最多保留的数量，带请求 ID 等属性的日志很少再次出现，不能无限累积
+const slogMaxSeen = 1024
+
+// 日志告警默认每分钟最多发送 20 条，避免错误风暴刷屏
+var DefaultSlogRateLimits = []RateLimit{
+	{Requests: 20, Per: time.Minute},
+}
+
+type SlogOption func(*slogCore)
+
+// WithSlogLevel 设置发送的最低级别，默认 slog.LevelError
+func WithSlogLevel(level slog.Leveler) SlogOption {
+	return func(c *slogCore) {
+		if level != nil {
+			c.level = level
+		}
+	}
+}
+
+func WithSlogFormat(format SlogFormat) SlogOption {
+	return func(c *slogCore) {
+		c.format = format
+	}
+}
+
+// WithSlogTitle 设置消息标题前缀，通常为服务名
+func WithSlogTitle(title string) SlogOption {
+	return func(c *slogCore) {
+		c.title = title
+	}
+}
+
+// WithSlogRateLimiter 设置发送频率限制，超出的日志被丢弃并在下一条消息中提示数量；传入 nil 关闭限制
+func WithSlogRateLimiter(limiter *RateLimiter) SlogOption {
+	return func(c *slogCore) {
+		c.limiter = limiter
+	}
+}
+
+// WithSlogDedupWindow 设置去重窗口，窗口内级别、内容和属性完全相同的日志只发送一次；传入 0 关闭去重
+func WithSlogDedupWindow(window time.Duration) SlogOption {
+	return func(c *slogCore) {
+		c.dedupWindow = window
+	}
+}
+
+// WithSlogQueueSize 设置后台发送队列的长度，队列满时丢弃新日志
+func WithSlogQueueSize(size int) SlogOption {
+	return func(c *slogCore) {
+		if size > 0 {
+			c.queueSize = size
+		}
+	}
+}
+
+// WithSlogSource 在消息中附带日志的源码位置
+func WithSlogSource() SlogOption {
+	return func(c *slogCore) {
+		c.addSource = true
+	}
+}
+
+func WithSlogClock(clock Clock) SlogOption {
+	return func(c *slogCore) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithSlogErrorLog 设置发送失败和丢弃日志时的输出，不要使用转发到自身的 logger
+func WithSlogErrorLog(logger *log.Logger) SlogOption {
+	return func(c *slogCore) {
+		c.logger = logger
+	}
+}
+
+type slogField struct {
+	key   string
+	value string
+}
+
+type slogSeen struct {
+	last    time.Time
+	repeats int
+}
+
+// slogCore 由 WithAttrs 和 WithGroup 派生出的 Handler 共享
+type slogCore struct {
+	level       slog.Leveler
+	format      SlogFormat
+	title       string
+	limiter     *RateLimiter
+	dedupWindow time.Duration
+	queueSize   int
+	addSource   bool
+	clock       Clock
+	logger      *log.Logger
+	async       *AsyncSender
+
+	mu        sync.Mutex
+	seen      map[string]*slogSeen
+	lastPrune time.Time
+	dropped   int
+}
+
+// SlogHandler 把达到指定级别的日志转发到飞书。Handle 只负责入队，不会阻塞调用方
+type SlogHandler struct {
+	core   *slogCore
+	attrs  []slogField
+	prefix string
+}
+
+func NewSlogHandler(sender Sender, opts ...SlogOption) *SlogHandler {
+	c := &slogCore{
+		level:       slog.LevelError,
+		limiter:     NewRateLimiter(DefaultSlogRateLimits...),
+		dedupWindow: DefaultSlogDedupWindow,
+		queueSize:   100,
+		clock:       SystemClock,
+		seen:        make(map[string]*slogSeen),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.async = NewAsyncSender(sender, WithQueueSize(c.queueSize), WithOverflowPolicy(OverflowDropNewest))
+	return &SlogHandler{core: c}
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.core.level.Level()
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := append([]slogField(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.prefix, a)
+		return true
+	})
+
+	c := h.core
+	repeats, dropped, ok := c.admit(slogKey(r, fields))
+	if !ok {
+		return nil
+	}
+
+	message, err := c.render(r, fields, repeats, dropped)
+	if err != nil {
+		return err
+	}
+	if err := c.async.Send(context.Background(), message, c.done); err != nil {
+		// 入队失败时把本条和它携带的丢弃数量留给下一条消息
+		c.drop(dropped+1, err)
+	}
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = append([]slogField(nil), h.attrs...)
+	for _, a := range attrs {
+		h2.attrs = appendSlogAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// Flush 等待已入队的日志发送完成
+func (h *SlogHandler) Flush(ctx context.Context) error {
+	return h.core.async.Flush(ctx)
+}
+
+// Close 发送完剩余日志后停止后台协程，之后的日志会被丢弃
+func (h *SlogHandler) Close(ctx context.Context) error {
+	return h.core.async.Close(ctx)
+}
+
+// admit 执行去重和频率限制，返回去重窗口内被合并的次数和此前被丢弃的条数
+func (c *slogCore) admit(key string) (repeats, dropped int, ok bool) {
+	now := c.clock.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dedupWindow > 0 {
+		if s := c.seen[key]; s != nil {
+			if now.Sub(s.last) < c.dedupWindow {
+				s.repeats++
+				return 0, 0, false
+			}
+			repeats = s.repeats
+		}
+		// 被合并过的记录尽量保留到同一条日志再次出现时报告重复次数，超过 slogMaxSeen 后同样清理
+		if now.Sub(c.lastPrune) >= c.dedupWindow {
+			for k, s := range c.seen {
+				if now.Sub(s.last) >= c.dedupWindow && (s.repeats == 0 || len(c.seen) > slogMaxSeen) {
+					delete(c.seen, k)
+				}
+			}
+			c.lastPrune = now
+		}
+	}
+
+	if c.limiter != nil && !c.limiter.Allow() {
+		c.dropped++
+		return 0, 0, false
+	}
+
+	if c.dedupWindow > 0 {
+		c.seen[key] = &slogSeen{last: now}
+	}
+	dropped, c.dropped = c.dropped, 0
+	return repeats, dropped, true
+}
+
+func (c *slogCore) drop(n int, err error) {
+	c.mu.Lock()
+	c.dropped += n
+	c.mu.Unlock()
+
+	if c.logger != nil {
+		c.logger.Printf("feishu: drop log record: %v", err)
+	}
+}
+
+func (c *slogCore) done(message *Message, err error) {
+	if err != nil && c.logger != nil {
+		c.logger.Printf("feishu: send log record failed: %v", err)
+	}
+}
+
+func slogColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "red"
+	case level >= slog.LevelWarn:
+		return "orange"
+	case level >= slog.LevelInfo:
+		return "blue"
+	}
+	return "grey"
+}
+
+func (c *slogCore) render(r slog.Record, fields []slogField, repeats, dropped int) (*Message, error) {
+	title := fmt.Sprintf("[%s] %s", r.Level, r.Message)
+	if c.title != "" {
+		title = c.title + " " + title
+	}
+
+	var notes []string
+	if !r.Time.IsZero() {
+		notes = append(notes, "时间 "+r.Time.Format("2006-01-02 15:04:05"))
+	}
+	if c.addSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		notes = append(notes, fmt.Sprintf("位置 %s:%d", frame.File, frame.Line))
+	}
+	if repeats > 0 {
+		notes = append(notes, fmt.Sprintf("上次发送后重复 %d 次", repeats))
+	}
+	if dropped > 0 {
+		notes = append(notes, fmt.Sprintf("因频率限制丢弃了 %d 条日志", dropped))
+	}
+
+	if c.format == SlogText {
+		lines := []string{title}
+		for _, f := range fields {
+			lines = append(lines, f.key+": "+f.value)
+		}
+		lines = append(lines, notes...)
+		return NewTextMessage(strings.Join(lines, "\n")), nil
+	}
+
+	builder := NewCardBuilder().Header(title, slogColor(r.Level))
+	if len(fields) > 0 {
+		cardFields := make([]*CardField, len(fields))
+		for i, f := range fields {
+			cardFields[i] = NewField(len(f.value) <= 40, NewLarkMd(fmt.Sprintf("**%s**\n%s", f.key, f.value)))
+		}
+		builder.Div(nil, cardFields...)
+	}
+	if len(notes) > 0 {
+		builder.Note(NewPlainText(strings.Join(notes, " · ")))
+	}
+	return builder.Build()
+}
+
+// appendSlogAttr 把属性展开为字段，分组以点号连接在键名前
+func appendSlogAttr(fields []slogField, prefix string, a slog.Attr) []slogField {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSlogAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+
+	value := a.Value.String()
+	if a.Value.Kind() == slog.KindTime {
+		value = a.Value.Time().Format(time.RFC3339)
+	}
+	return append(fields, slogField{key: prefix + a.Key, value: value})
+}
+
+func slogKey(r slog.Record, fields []slogField) string {
+	var sb strings.Builder
+	sb.WriteString(r.Level.String())
+	sb.WriteByte(0)
+	sb.WriteString(r.Message)
+	for _, f := range fields {
+		sb.WriteByte(0)
+		sb.WriteString(f.key)
+		sb.WriteByte('=')
+		sb.WriteString(f.value)
+	}
+	return sb.String()
+}
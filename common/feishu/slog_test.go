@@ -0,0 +1,239 @@
+package feishu
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+func (s *recordingSender) SendMessageContext(ctx context.Context, message *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func (s *recordingSender) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Message(nil), s.messages...)
+}
+
+func flushSlog(t *testing.T, h *SlogHandler) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	t.Run("文本格式与属性分组", func(t *testing.T) {
+		sender := &recordingSender{}
+		h := NewSlogHandler(sender, WithSlogTitle("api"))
+		defer h.Close(context.Background())
+
+		logger := slog.New(h).With("env", "prod").WithGroup("req")
+		logger.Info("忽略低级别日志")
+		logger.Error("请求失败", "id", 42, slog.Group("user", "name", "bob"), slog.Group("empty"))
+		flushSlog(t, h)
+
+		messages := sender.Messages()
+		if len(messages) != 1 {
+			t.Fatalf("got %d messages, want 1", len(messages))
+		}
+		text := textOf(messages[0])
+		for _, want := range []string{"api [ERROR] 请求失败", "env: prod", "req.id: 42", "req.user.name: bob", "时间 "} {
+			if !strings.Contains(text, want) {
+				t.Errorf("text should contain %q:\n%s", want, text)
+			}
+		}
+		if strings.Contains(text, "empty") {
+			t.Errorf("empty group should be omitted:\n%s", text)
+		}
+	})
+
+	t.Run("卡片格式", func(t *testing.T) {
+		sender := &recordingSender{}
+		h := NewSlogHandler(sender, WithSlogFormat(SlogCard), WithSlogLevel(slog.LevelWarn), WithSlogSource())
+		defer h.Close(context.Background())
+
+		slog.New(h).Warn("磁盘空间不足", "mount", "/data", "free", "3%")
+		flushSlog(t, h)
+
+		messages := sender.Messages()
+		if len(messages) != 1 {
+			t.Fatalf("got %d messages, want 1", len(messages))
+		}
+		if err := messages[0].Validate(); err != nil {
+			t.Fatalf("Validate() error: %v", err)
+		}
+		card := messages[0].Content.(*InteractiveContent)
+		if card.Header.Template != "orange" || card.Header.Title.Content != "[WARN] 磁盘空间不足" {
+			t.Errorf("header = %q, %q", card.Header.Template, card.Header.Title.Content)
+		}
+		data, _ := json.Marshal(card)
+		for _, want := range []string{`**mount**\n/data`, `**free**\n3%`, "slog_test.go:"} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("card should contain %q:\n%s", want, data)
+			}
+		}
+	})
+
+	t.Run("去重", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+		sender := &recordingSender{}
+		h := NewSlogHandler(sender, WithSlogClock(ClockFunc(func() time.Time { return now })), WithSlogRateLimiter(nil))
+		defer h.Close(context.Background())
+
+		logger := slog.New(h)
+		for i := 0; i < 3; i++ {
+			logger.Error("连接失败", "host", "db")
+		}
+		logger.Error("连接失败", "host", "cache")
+		flushSlog(t, h)
+		if got := len(sender.Messages()); got != 2 {
+			t.Fatalf("got %d messages within window, want 2", got)
+		}
+
+		now = now.Add(DefaultSlogDedupWindow)
+		logger.Error("连接失败", "host", "db")
+		flushSlog(t, h)
+		messages := sender.Messages()
+		if len(messages) != 3 {
+			t.Fatalf("got %d messages after window, want 3", len(messages))
+		}
+		if text := textOf(messages[2]); !strings.Contains(text, "上次发送后重复 2 次") {
+			t.Errorf("text should mention repeats:\n%s", text)
+		}
+	})
+
+	t.Run("其它日志触发清理后仍报告重复次数", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+		sender := &recordingSender{}
+		h := NewSlogHandler(sender, WithSlogClock(ClockFunc(func() time.Time { return now })), WithSlogRateLimiter(nil))
+		defer h.Close(context.Background())
+
+		logger := slog.New(h)
+		logger.Error("连接失败", "host", "db")
+		logger.Error("连接失败", "host", "db")
+		logger.Error("连接失败", "host", "db")
+
+		now = now.Add(2 * DefaultSlogDedupWindow)
+		logger.Error("磁盘已满")
+		logger.Error("连接失败", "host", "db")
+		flushSlog(t, h)
+
+		messages := sender.Messages()
+		if len(messages) != 3 {
+			t.Fatalf("got %d messages, want 3", len(messages))
+		}
+		if text := textOf(messages[2]); !strings.Contains(text, "上次发送后重复 2 次") {
+			t.Errorf("text should mention repeats:\n%s", text)
+		}
+	})
+
+	t.Run("过期的重复记录不会无限累积", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+		h := NewSlogHandler(senderFunc(func(ctx context.Context, message *Message) error { return nil }),
+			WithSlogClock(ClockFunc(func() time.Time { return now })), WithSlogRateLimiter(nil))
+		defer h.Close(context.Background())
+
+		logger := slog.New(h)
+		for i := 0; i < 3*slogMaxSeen; i++ {
+			logger.Error("请求失败", "request_id", i)
+			logger.Error("请求失败", "request_id", i)
+		}
+
+		now = now.Add(2 * DefaultSlogDedupWindow)
+		logger.Error("磁盘已满")
+
+		h.core.mu.Lock()
+		seen := len(h.core.seen)
+		h.core.mu.Unlock()
+		if seen > slogMaxSeen+1 {
+			t.Errorf("seen = %d, want <= %d", seen, slogMaxSeen+1)
+		}
+	})
+
+	t.Run("频率限制", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+		limiter := NewRateLimiter(RateLimit{Requests: 1, Per: time.Minute})
+		limiter.now = func() time.Time { return now }
+
+		sender := &recordingSender{}
+		h := NewSlogHandler(sender, WithSlogRateLimiter(limiter), WithSlogDedupWindow(0))
+		defer h.Close(context.Background())
+
+		logger := slog.New(h)
+		for i := 0; i < 4; i++ {
+			logger.Error("失败", "n", i)
+		}
+		flushSlog(t, h)
+		if got := len(sender.Messages()); got != 1 {
+			t.Fatalf("got %d messages, want 1", got)
+		}
+
+		now = now.Add(time.Minute)
+		logger.Error("失败", "n", 4)
+		flushSlog(t, h)
+		messages := sender.Messages()
+		if len(messages) != 2 {
+			t.Fatalf("got %d messages, want 2", len(messages))
+		}
+		if text := textOf(messages[1]); !strings.Contains(text, "因频率限制丢弃了 3 条日志") {
+			t.Errorf("text should mention dropped records:\n%s", text)
+		}
+	})
+
+	t.Run("不阻塞调用方", func(t *testing.T) {
+		release := make(chan struct{})
+		started := make(chan struct{}, 1)
+		h := NewSlogHandler(senderFunc(func(ctx context.Context, message *Message) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}), WithSlogQueueSize(1), WithSlogRateLimiter(nil), WithSlogDedupWindow(0))
+
+		logger := slog.New(h)
+		logger.Error("第一条")
+		<-started
+
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < 10; i++ {
+				logger.Error("后续", "n", i)
+			}
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Handle() blocked while sender is busy")
+		}
+
+		h.core.mu.Lock()
+		dropped := h.core.dropped
+		h.core.mu.Unlock()
+		if dropped != 9 {
+			t.Errorf("dropped = %d, want 9", dropped)
+		}
+
+		close(release)
+		if err := h.Close(context.Background()); err != nil {
+			t.Errorf("Close() error: %v", err)
+		}
+	})
+}
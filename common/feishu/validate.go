@@ -0,0 +1,193 @@
+package feishu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError 描述一个校验失败的字段
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors 汇总全部校验失败的字段
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func joinPath(base, field string) string {
+	if base == "" {
+		return field
+	}
+	if strings.HasPrefix(field, "[") {
+		return base + field
+	}
+	return base + "." + field
+}
+
+func indexPath(base, field string, i int) string {
+	return joinPath(base, fmt.Sprintf("%s[%d]", field, i))
+}
+
+// contentMessageType 返回内容类型对应的消息类型，用于检查 MsgType 与 Content 是否匹配
+func contentMessageType(content interface{}) (MessageType, bool) {
+	switch content.(type) {
+	case *TextContent:
+		return MessageTypeText, true
+	case *RichTextContent:
+		return MessageTypeRichText, true
+	case *InteractiveContent, *CardV2, *TemplateCardContent:
+		return MessageTypeInteractive, true
+	case *ImageContent:
+		return MessageTypeImage, true
+	case *ShareChatContent:
+		return MessageTypeShareChat, true
+	}
+	return "", false
+}
+
+// Validate 在发送前检查消息，返回的 ValidationErrors 列出全部问题及字段路径
+func (m *Message) Validate() error {
+	v := &validator{}
+	if m == nil {
+		v.add("", "message is nil")
+		return v.err()
+	}
+	m.validate(v)
+	return v.err()
+}
+
+func (m *Message) validate(v *validator) {
+	switch m.MsgType {
+	case "":
+		v.add("msg_type", "is empty")
+	case MessageTypeText, MessageTypeRichText, MessageTypeInteractive, MessageTypeImage, MessageTypeShareChat:
+	default:
+		v.add("msg_type", "unsupported message type %q", m.MsgType)
+	}
+
+	if m.Content == nil {
+		v.add("content", "is nil")
+		return
+	}
+	if want, ok := contentMessageType(m.Content); ok && m.MsgType != "" && want != m.MsgType {
+		v.add("msg_type", "%q does not match content type %T", m.MsgType, m.Content)
+	}
+
+	switch content := m.Content.(type) {
+	case *TextContent:
+		if strings.TrimSpace(content.Text) == "" {
+			v.add("content.text", "is empty")
+		}
+	case *RichTextContent:
+		validatePost(v, "content.post", content.Post)
+	case *InteractiveContent:
+		validateInteractive(v, "content", content)
+	case *CardV2:
+		content.validate(v, "content")
+	case *TemplateCardContent:
+		validateTemplateCard(v, "content", content)
+	case *ImageContent:
+		if content.ImageKey == "" {
+			v.add("content.image_key", "is empty")
+		}
+	case *ShareChatContent:
+		if content.ShareChatId == "" {
+			v.add("content.share_chat_id", "is empty")
+		}
+	}
+}
+
+func validatePost(v *validator, path string, post *Post) {
+	if post == nil {
+		v.add(path, "is nil")
+		return
+	}
+	locales := post.Locales()
+	if len(locales) == 0 {
+		v.add(path, "has no locale")
+		return
+	}
+	for _, locale := range locales {
+		content := post.Get(locale)
+		if content.Title == "" && len(content.Content) == 0 {
+			v.add(joinPath(path, string(locale)), "is empty")
+		}
+	}
+	post.validate(v, path)
+}
+
+func validateInteractive(v *validator, path string, content *InteractiveContent) {
+	if content.Header != nil {
+		if content.Header.Title == nil {
+			v.add(joinPath(path, "header.title"), "is nil")
+		} else if content.Header.Title.Content == "" {
+			v.add(joinPath(path, "header.title.content"), "is empty")
+		}
+	}
+
+	if len(content.Elements) == 0 {
+		v.add(joinPath(path, "elements"), "is empty")
+		return
+	}
+	for i, element := range content.Elements {
+		elementPath := indexPath(path, "elements", i)
+		switch e := element.(type) {
+		case nil:
+			v.add(elementPath, "is nil")
+		case CardElement:
+			validateBodyElement(v, elementPath, e)
+		case map[string]interface{}:
+			if tag, _ := e["tag"].(string); tag == "" {
+				v.add(joinPath(elementPath, "tag"), "is empty")
+			}
+		}
+	}
+}
+
+func validateTemplateCard(v *validator, path string, content *TemplateCardContent) {
+	if content.Type != "template" {
+		v.add(joinPath(path, "type"), "must be %q, got %q", "template", content.Type)
+	}
+	if content.Data == nil {
+		v.add(joinPath(path, "data"), "is nil")
+		return
+	}
+	if content.Data.TemplateID == "" {
+		v.add(joinPath(path, "data.template_id"), "is empty")
+	}
+	for name := range content.Data.TemplateVariable {
+		if name == "" {
+			v.add(joinPath(path, "data.template_variable"), "contains an empty variable name")
+		}
+	}
+}
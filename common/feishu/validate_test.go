@@ -0,0 +1,126 @@
+package feishu
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func fieldsOf(t *testing.T, err error) []string {
+	t.Helper()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("err = %v, want ValidationErrors", err)
+	}
+	fields := make([]string, len(errs))
+	for i, fe := range errs {
+		fields[i] = fe.Field
+	}
+	return fields
+}
+
+func TestMessageValidate(t *testing.T) {
+	valid := []struct {
+		name    string
+		message *Message
+	}{
+		{"文本", NewTextMessage("hello")},
+		{"富文本", NewRichTextMessage("标题", [][]RichTextElement{{NewTextElement("内容")}})},
+		{"旧版卡片", NewInteractiveMessage(nil, CreateCardHeader("标题", "blue"), []interface{}{
+			map[string]interface{}{"tag": "hr"},
+		})},
+		{"卡片构建器", NewInteractiveMessage(nil, nil, []interface{}{NewMarkdown("**hi**")})},
+		{"卡片 2.0", NewCardV2Message(NewCardV2().Add(NewMarkdown("hi")))},
+		{"图片", NewImageMessage("img_key")},
+		{"群名片", NewShareChatMessage("oc_123")},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.message.Validate(); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name    string
+		message *Message
+		fields  []string
+	}{
+		{"空文本", NewTextMessage("  "), []string{"content.text"}},
+		{"空图片", NewImageMessage(""), []string{"content.image_key"}},
+		{"空群名片", NewShareChatMessage(""), []string{"content.share_chat_id"}},
+		{"空卡片", NewInteractiveMessage(nil, nil, nil), []string{"content.elements"}},
+		{"卡片标题为空", NewInteractiveMessage(nil, CreateCardHeader("", "blue"), []interface{}{NewHr()}), []string{"content.header.title.content"}},
+		{"卡片元素", NewInteractiveMessage(nil, nil, []interface{}{nil, map[string]interface{}{}, NewImg("", "alt")}),
+			[]string{"content.elements[0]", "content.elements[1].tag", "content.elements[2].img_key"}},
+		{"卡片顶层文本", NewInteractiveMessage(nil, nil, []interface{}{NewLarkMd("**hi**")}), []string{"content.elements[0]"}},
+		{"卡片 nil 组件", NewInteractiveMessage(nil, nil, []interface{}{(*MarkdownElement)(nil)}), []string{"content.elements[0]"}},
+		{"富文本为空", &Message{MsgType: MessageTypeRichText, Content: &RichTextContent{}}, []string{"content.post"}},
+		{"富文本元素", NewRichTextMessage("标题", [][]RichTextElement{{{Tag: RichTextTagLink, Text: "x"}}}),
+			[]string{"content.post.zh_cn.content[0][0].href"}},
+		{"卡片 2.0 无元素", NewCardV2Message(NewCardV2()), []string{"content.body.elements"}},
+		{"模板卡片", &Message{MsgType: MessageTypeInteractive, Content: &TemplateCardContent{Data: &TemplateCardData{}}},
+			[]string{"content.type", "content.data.template_id"}},
+		{"类型不匹配", &Message{MsgType: MessageTypeImage, Content: &TextContent{Text: "hi"}}, []string{"msg_type"}},
+		{"类型为空", &Message{Content: &TextContent{Text: "hi"}}, []string{"msg_type"}},
+		{"未知类型", &Message{MsgType: "audio", Content: map[string]interface{}{}}, []string{"msg_type"}},
+		{"内容为空", &Message{MsgType: MessageTypeText}, []string{"content"}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := fieldsOf(t, tt.message.Validate())
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+
+	t.Run("nil 消息", func(t *testing.T) {
+		var message *Message
+		if err := message.Validate(); err == nil {
+			t.Error("Validate() should fail for nil message")
+		}
+	})
+
+	t.Run("列出全部错误", func(t *testing.T) {
+		message := &Message{MsgType: MessageTypeShareChat, Content: &ImageContent{}}
+		err := message.Validate()
+		fields := fieldsOf(t, err)
+		if len(fields) != 2 {
+			t.Errorf("fields = %v, want msg_type and content.image_key", fields)
+		}
+		if !strings.HasPrefix(err.Error(), "validation failed: ") {
+			t.Errorf("Error() = %q", err.Error())
+		}
+	})
+}
+
+func TestSendMessageValidation(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		successHandler(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(server.URL)
+	if err := client.SendText(""); err == nil {
+		t.Error("SendText(\"\") should fail validation")
+	}
+	if calls != 0 {
+		t.Errorf("calls = %d, want 0", calls)
+	}
+
+	t.Run("关闭校验", func(t *testing.T) {
+		client := NewClientWithOptions(server.URL, WithoutValidation())
+		if err := client.SendText(""); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+}
@@ -0,0 +1,323 @@
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrWriterClosed = errors.New("feishu: writer closed")
+
+type WriterFormat int
+
+const (
+	// WriterText 每批输出发送为一条文本消息
+	WriterText WriterFormat = iota
+	// WriterCodeBlock 每批输出发送为包含代码块的富文本消息，保留缩进和等宽排版
+	WriterCodeBlock
+)
+
+const (
+	DefaultFlushInterval = 2 * time.Second
+	DefaultFlushSize     = 4 << 10
+)
+
+// 终端颜色等 ANSI 控制序列在飞书中无法显示
+var ansiEscapeRe = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+type WriterOption func(*Writer)
+
+func WithWriterFormat(format WriterFormat) WriterOption {
+	return func(w *Writer) {
+		w.format = format
+	}
+}
+
+// WithWriterTitle 设置消息标题，文本格式下作为第一行
+func WithWriterTitle(title string) WriterOption {
+	return func(w *Writer) {
+		w.title = title
+	}
+}
+
+// WithWriterLanguage 设置代码块的语言，仅在 WriterCodeBlock 格式下生效
+func WithWriterLanguage(language string) WriterOption {
+	return func(w *Writer) {
+		w.language = language
+	}
+}
+
+// WithFlushInterval 设置定时发送的间隔，传入 0 时只在达到大小阈值或 Flush、Close 时发送
+func WithFlushInterval(interval time.Duration) WriterOption {
+	return func(w *Writer) {
+		if interval >= 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// WithFlushSize 设置单条消息累积的字节数上限，达到后立即发送
+func WithFlushSize(size int) WriterOption {
+	return func(w *Writer) {
+		if size > 0 {
+			w.size = size
+		}
+	}
+}
+
+// WithWriterErrorLog 设置定时发送和 Write 中发送失败时的输出，不要使用写入自身的 logger
+func WithWriterErrorLog(logger *log.Logger) WriterOption {
+	return func(w *Writer) {
+		w.logger = logger
+	}
+}
+
+// 发送失败的内容会保留到下次发送，超过 size 的该倍数后丢弃最早的行
+const writerMaxBuffered = 16
+
+// Writer 把写入的内容按行缓存，达到大小阈值或定时批量发送，可以作为 log.Logger 或 exec.Cmd 的输出
+type Writer struct {
+	sender   Sender
+	format   WriterFormat
+	title    string
+	language string
+	interval time.Duration
+	size     int
+	logger   *log.Logger
+
+	mu      sync.Mutex
+	partial []byte
+	lines   []string
+	pending int
+	closed  bool
+
+	// sendMu 保证批次按写入顺序发送
+	sendMu sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func NewWriter(sender Sender, opts ...WriterOption) *Writer {
+	w := &Writer{
+		sender:   sender,
+		interval: DefaultFlushInterval,
+		size:     DefaultFlushSize,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	if w.interval > 0 {
+		go w.loop()
+	} else {
+		close(w.done)
+	}
+	return w
+}
+
+// Write 缓存完整的行，未以换行结尾的内容等到后续写入或 Close 时发送，超过大小阈值的行会被切开。
+// 达到大小阈值时在当前调用中同步发送；发送失败只记录到 WithWriterErrorLog 并保留内容等待下次发送，
+// 不会返回给调用方，避免 exec.Cmd 等因一次飞书请求失败而中断输出
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, ErrWriterClosed
+	}
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.appendLine(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+	// 没有换行的超长输出（例如进度条）也按大小阈值切开
+	for len(w.partial) > w.size {
+		cut := w.cut(w.partial)
+		w.appendLine(w.partial[:cut])
+		w.partial = w.partial[cut:]
+	}
+	w.partial = append([]byte(nil), w.partial...)
+	full := w.pending >= w.size
+	w.mu.Unlock()
+
+	if full {
+		w.logError(w.FlushContext(w.ctx))
+	}
+	return len(p), nil
+}
+
+func (w *Writer) Flush() error {
+	return w.FlushContext(context.Background())
+}
+
+// FlushContext 立即发送已缓存的完整行，失败的批次及其后的内容保留到下次发送
+func (w *Writer) FlushContext(ctx context.Context) error {
+	w.sendMu.Lock()
+	defer w.sendMu.Unlock()
+
+	w.mu.Lock()
+	batches := w.take()
+	w.mu.Unlock()
+
+	for i, batch := range batches {
+		// 只有空行的批次无法通过校验，直接跳过
+		if strings.TrimSpace(strings.Join(batch, "")) == "" {
+			continue
+		}
+		if err := w.sender.SendMessageContext(ctx, w.message(batch)); err != nil {
+			w.requeue(batches[i:])
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Writer) Close() error {
+	return w.CloseContext(context.Background())
+}
+
+// CloseContext 停止定时发送，并把剩余内容（包括没有换行结尾的部分）全部发送；
+// ctx 结束时中断正在进行的发送
+func (w *Writer) CloseContext(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	if len(w.partial) > 0 {
+		w.appendLine(w.partial)
+		w.partial = nil
+	}
+	w.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, w.cancel)
+	defer stop()
+	defer w.cancel()
+
+	close(w.stop)
+	<-w.done
+	return w.FlushContext(ctx)
+}
+
+func (w *Writer) loop() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.logError(w.FlushContext(w.ctx))
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+func (w *Writer) logError(err error) {
+	if err != nil && w.logger != nil {
+		w.logger.Printf("feishu: flush writer failed: %v", err)
+	}
+}
+
+// cut 返回不超过 size 且不切断 UTF-8 字符的切分位置
+func (w *Writer) cut(line []byte) int {
+	cut := w.size
+	for cut > 1 && !utf8.RuneStart(line[cut]) {
+		cut--
+	}
+	return cut
+}
+
+// appendLine 在持有 mu 时调用，超过大小阈值的行切成多行
+func (w *Writer) appendLine(line []byte) {
+	for len(line) > w.size {
+		cut := w.cut(line)
+		w.appendText(line[:cut])
+		line = line[cut:]
+	}
+	w.appendText(line)
+}
+
+func (w *Writer) appendText(line []byte) {
+	text := ansiEscapeRe.ReplaceAllString(strings.TrimSuffix(string(line), "\r"), "")
+	w.lines = append(w.lines, text)
+	w.pending += len(text) + 1
+}
+
+// requeue 把发送失败的批次放回缓存最前面
+func (w *Writer) requeue(batches [][]string) {
+	var lines []string
+	for _, batch := range batches {
+		lines = append(lines, batch...)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lines = append(lines, w.lines...)
+	w.pending = 0
+	for _, line := range w.lines {
+		w.pending += len(line) + 1
+	}
+
+	dropped := 0
+	for w.pending > writerMaxBuffered*w.size && len(w.lines) > 0 {
+		w.pending -= len(w.lines[0]) + 1
+		w.lines = w.lines[1:]
+		dropped++
+	}
+	if dropped > 0 && w.logger != nil {
+		w.logger.Printf("feishu: writer buffer full, dropped %d lines", dropped)
+	}
+}
+
+// take 在持有 mu 时调用，取出全部缓存的行并按大小阈值分批
+func (w *Writer) take() [][]string {
+	var batches [][]string
+	var batch []string
+	size := 0
+	for _, line := range w.lines {
+		if len(batch) > 0 && size+len(line)+1 > w.size {
+			batches = append(batches, batch)
+			batch, size = nil, 0
+		}
+		batch = append(batch, line)
+		size += len(line) + 1
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	w.lines = nil
+	w.pending = 0
+	return batches
+}
+
+func (w *Writer) message(lines []string) *Message {
+	text := strings.Join(lines, "\n")
+	if w.format == WriterCodeBlock {
+		return NewRichTextMessage(w.title, [][]RichTextElement{{NewCodeBlockElement(w.language, text)}})
+	}
+	if w.title != "" {
+		text = w.title + "\n" + text
+	}
+	return NewTextMessage(text)
+}
@@ -0,0 +1,205 @@
+package feishu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriter(t *testing.T) {
+	t.Run("按行缓存直到 Close", func(t *testing.T) {
+		sender := &recordingSender{}
+		w := NewWriter(sender, WithFlushInterval(0), WithWriterTitle("构建日志"))
+
+		fmt.Fprint(w, "第一行\r\n\x1b[32m第二")
+		fmt.Fprint(w, "行\x1b[0m\n未结束")
+		if got := len(sender.Messages()); got != 0 {
+			t.Fatalf("got %d messages before Close", got)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error: %v", err)
+		}
+		messages := sender.Messages()
+		if len(messages) != 1 {
+			t.Fatalf("got %d messages, want 1", len(messages))
+		}
+		if got, want := textOf(messages[0]), "构建日志\n第一行\n第二行\n未结束"; got != want {
+			t.Errorf("text = %q, want %q", got, want)
+		}
+
+		if _, err := w.Write([]byte("late\n")); !errors.Is(err, ErrWriterClosed) {
+			t.Errorf("Write() after Close = %v, want ErrWriterClosed", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Errorf("second Close() error: %v", err)
+		}
+	})
+
+	t.Run("达到大小阈值时分批发送", func(t *testing.T) {
+		sender := &recordingSender{}
+		w := NewWriter(sender, WithFlushInterval(0), WithFlushSize(16))
+		defer w.Close()
+
+		if _, err := w.Write([]byte("aaaa\nbbbb\ncccc\ndddd\neeee\n")); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		messages := sender.Messages()
+		if len(messages) != 2 {
+			t.Fatalf("got %d messages, want 2", len(messages))
+		}
+		if textOf(messages[0]) != "aaaa\nbbbb\ncccc" || textOf(messages[1]) != "dddd\neeee" {
+			t.Errorf("texts = %q, %q", textOf(messages[0]), textOf(messages[1]))
+		}
+	})
+
+	t.Run("超长无换行内容按字符边界切开", func(t *testing.T) {
+		sender := &recordingSender{}
+		w := NewWriter(sender, WithFlushInterval(0), WithFlushSize(10))
+
+		w.Write([]byte(strings.Repeat("进度", 5)))
+		w.Close()
+		for _, message := range sender.Messages() {
+			if text := textOf(message); !utf8.ValidString(text) || len(text) > 10 {
+				t.Errorf("text = %q", text)
+			}
+		}
+	})
+
+	t.Run("以换行结尾的超长行同样切开", func(t *testing.T) {
+		sender := &recordingSender{}
+		w := NewWriter(sender, WithFlushInterval(0))
+
+		if _, err := w.Write([]byte(strings.Repeat("a", 30000) + "\n")); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+		w.Close()
+		messages := sender.Messages()
+		if len(messages) != 8 {
+			t.Fatalf("got %d messages, want 8", len(messages))
+		}
+		total := 0
+		for _, message := range messages {
+			text := textOf(message)
+			if len(text) > DefaultFlushSize {
+				t.Errorf("message size = %d, want <= %d", len(text), DefaultFlushSize)
+			}
+			total += len(text)
+		}
+		if total != 30000 {
+			t.Errorf("total = %d, want 30000", total)
+		}
+	})
+
+	t.Run("Write 不返回发送错误并保留内容", func(t *testing.T) {
+		var mu sync.Mutex
+		fail := true
+		sender := &recordingSender{}
+		var logs strings.Builder
+		w := NewWriter(senderFunc(func(ctx context.Context, message *Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if fail {
+				return errors.New("down")
+			}
+			return sender.SendMessageContext(ctx, message)
+		}), WithFlushInterval(0), WithFlushSize(8), WithWriterErrorLog(log.New(&logs, "", 0)))
+
+		if _, err := w.Write([]byte("aaaa\nbbbb\n")); err != nil {
+			t.Errorf("Write() error = %v, want nil", err)
+		}
+		if !strings.Contains(logs.String(), "down") {
+			t.Errorf("flush error should be logged, got %q", logs.String())
+		}
+
+		mu.Lock()
+		fail = false
+		mu.Unlock()
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error: %v", err)
+		}
+		var texts []string
+		for _, message := range sender.Messages() {
+			texts = append(texts, textOf(message))
+		}
+		if strings.Join(texts, "|") != "aaaa|bbbb" {
+			t.Errorf("texts = %q", texts)
+		}
+	})
+
+	t.Run("CloseContext 中断挂起的发送", func(t *testing.T) {
+		w := NewWriter(senderFunc(func(ctx context.Context, message *Message) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}), WithFlushInterval(0))
+
+		fmt.Fprintln(w, "hang")
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := w.CloseContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("CloseContext() = %v, want DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("定时发送", func(t *testing.T) {
+		sender := &recordingSender{}
+		w := NewWriter(sender, WithFlushInterval(10*time.Millisecond))
+		defer w.Close()
+
+		log.New(w, "", 0).Print("部署完成")
+
+		deadline := time.Now().Add(time.Second)
+		for len(sender.Messages()) == 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		messages := sender.Messages()
+		if len(messages) != 1 || textOf(messages[0]) != "部署完成" {
+			t.Fatalf("messages = %+v", messages)
+		}
+	})
+
+	t.Run("代码块格式", func(t *testing.T) {
+		sender := &recordingSender{}
+		w := NewWriter(sender, WithFlushInterval(0), WithWriterFormat(WriterCodeBlock), WithWriterTitle("go test"), WithWriterLanguage("shell"))
+
+		fmt.Fprintln(w, "  --- FAIL: TestX")
+		fmt.Fprintln(w)
+		w.Close()
+
+		messages := sender.Messages()
+		if len(messages) != 1 || messages[0].MsgType != MessageTypeRichText {
+			t.Fatalf("messages = %+v", messages)
+		}
+		if err := messages[0].Validate(); err != nil {
+			t.Fatalf("Validate() error: %v", err)
+		}
+		post := messages[0].Content.(*RichTextContent).Post.ZhCn
+		element := post.Content[0][0]
+		if post.Title != "go test" || element.Tag != RichTextTagCodeBlock || element.Language != "shell" || element.Text != "  --- FAIL: TestX\n" {
+			t.Errorf("post = %+v", post)
+		}
+	})
+
+	t.Run("跳过空行批次并返回发送错误", func(t *testing.T) {
+		var calls int
+		w := NewWriter(senderFunc(func(ctx context.Context, message *Message) error {
+			calls++
+			return errors.New("down")
+		}), WithFlushInterval(0))
+
+		fmt.Fprint(w, "\n\n")
+		if err := w.Flush(); err != nil || calls != 0 {
+			t.Errorf("Flush() = %v, calls = %d", err, calls)
+		}
+		fmt.Fprintln(w, "失败")
+		if err := w.Close(); err == nil || calls != 1 {
+			t.Errorf("Close() = %v, calls = %d", err, calls)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+// Package gitevent 把 GitHub 和 GitLab 的 webhook 事件转换为简洁的飞书消息
+package gitevent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+// ErrIgnored 表示事件不需要通知，例如 ping、草稿状态变化或未完成的流水线
+var ErrIgnored = errors.New("gitevent: event ignored")
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// 统一后的事件类型，GitLab 的 merge request 和 pipeline 分别对应 pull_request 和 workflow_run
+const (
+	KindPush        = "push"
+	KindPullRequest = "pull_request"
+	KindIssue       = "issues"
+	KindWorkflowRun = "workflow_run"
+	KindRelease     = "release"
+)
+
+// 统一后的动作
+const (
+	ActionOpened    = "opened"
+	ActionClosed    = "closed"
+	ActionReopened  = "reopened"
+	ActionMerged    = "merged"
+	ActionPublished = "published"
+	ActionCompleted = "completed"
+)
+
+// 统一后的流水线结果
+const (
+	ConclusionSuccess   = "success"
+	ConclusionFailure   = "failure"
+	ConclusionCancelled = "cancelled"
+)
+
+const (
+	DefaultMaxCommits = 10
+	maxBodyRunes      = 300
+)
+
+type Commit struct {
+	ID      string
+	Message string
+	Author  string
+	URL     string
+}
+
+// Event 是 GitHub 和 GitLab 事件的公共表示
+type Event struct {
+	Provider string
+	Kind     string
+	Action   string
+	Repo     string
+	RepoURL  string
+	Actor    string
+
+	// Number、Title、Body 和 URL 描述 PR、issue、release 或流水线本身
+	Number int
+	Title  string
+	Body   string
+	URL    string
+
+	Branch       string
+	TargetBranch string
+	Commits      []Commit
+	TotalCommits int
+	CompareURL   string
+	Forced       bool
+	Conclusion   string
+}
+
+// Message 把事件转换为飞书消息：推送事件使用富文本，其余事件使用卡片
+func (e *Event) Message(maxCommits int) (*feishu.Message, error) {
+	switch e.Kind {
+	case KindPush:
+		return e.pushPost(maxCommits), nil
+	case KindPullRequest, KindIssue:
+		return e.itemCard()
+	case KindWorkflowRun:
+		return e.workflowCard()
+	case KindRelease:
+		return e.releaseCard()
+	}
+	return nil, fmt.Errorf("gitevent: unsupported event kind %q", e.Kind)
+}
+
+func (e *Event) pushPost(maxCommits int) *feishu.Message {
+	total := e.TotalCommits
+	if total < len(e.Commits) {
+		total = len(e.Commits)
+	}
+	verb := "推送了"
+	if e.Forced {
+		verb = "强制推送了"
+	}
+	title := fmt.Sprintf("[%s] %s %s %d 个提交到 %s", e.Repo, e.Actor, verb, total, e.Branch)
+
+	var rows [][]feishu.RichTextElement
+	for i, commit := range e.Commits {
+		if i == maxCommits {
+			break
+		}
+		id := feishu.NewTextElement(shortID(commit.ID))
+		if commit.URL != "" {
+			id = feishu.NewLinkElement(shortID(commit.ID), commit.URL)
+		}
+		row := []feishu.RichTextElement{id, feishu.NewTextElement(" " + firstLine(commit.Message))}
+		if commit.Author != "" && commit.Author != e.Actor {
+			row = append(row, feishu.NewTextElement(" - "+commit.Author, feishu.StyleItalic))
+		}
+		rows = append(rows, row)
+	}
+	if hidden := total - len(rows); hidden > 0 {
+		rows = append(rows, []feishu.RichTextElement{feishu.NewTextElement(fmt.Sprintf("……还有 %d 个提交", hidden))})
+	}
+	if e.CompareURL != "" {
+		rows = append(rows, []feishu.RichTextElement{feishu.NewLinkElement("查看对比", e.CompareURL)})
+	}
+	return feishu.NewRichTextMessage(title, rows)
+}
+
+var itemNames = map[string]string{
+	KindPullRequest: "PR",
+	KindIssue:       "Issue",
+}
+
+var actionVerbs = map[string]string{
+	ActionOpened:   "创建了",
+	ActionClosed:   "关闭了",
+	ActionReopened: "重新打开了",
+	ActionMerged:   "合并了",
+}
+
+var actionColors = map[string]string{
+	ActionOpened:   "blue",
+	ActionReopened: "blue",
+	ActionMerged:   "purple",
+	ActionClosed:   "grey",
+}
+
+func (e *Event) itemCard() (*feishu.Message, error) {
+	name := itemNames[e.Kind]
+	builder := feishu.NewCardBuilder().
+		Header(fmt.Sprintf("[%s] %s #%d %s", e.Repo, name, e.Number, e.Title), actionColors[e.Action])
+
+	summary := fmt.Sprintf("**%s** %s %s", e.Actor, actionVerbs[e.Action], name)
+	if e.Kind == KindPullRequest && e.Branch != "" {
+		summary += fmt.Sprintf("\n%s → %s", e.Branch, e.TargetBranch)
+	}
+	builder.Markdown(summary)
+	if body := truncate(e.Body, maxBodyRunes); body != "" {
+		builder.Div(feishu.NewPlainText(body))
+	}
+	builder.Action(feishu.NewButton("查看 "+name, feishu.ButtonPrimary).WithURL(e.URL))
+	return builder.Build()
+}
+
+var conclusions = map[string]struct{ text, color string }{
+	ConclusionSuccess:   {"成功", "green"},
+	ConclusionFailure:   {"失败", "red"},
+	ConclusionCancelled: {"已取消", "grey"},
+}
+
+func (e *Event) workflowCard() (*feishu.Message, error) {
+	result, ok := conclusions[e.Conclusion]
+	if !ok {
+		result = conclusions[ConclusionFailure]
+		result.text = e.Conclusion
+	}
+
+	builder := feishu.NewCardBuilder().
+		Header(fmt.Sprintf("[%s] %s #%d %s", e.Repo, e.Title, e.Number, result.text), result.color).
+		Div(nil,
+			feishu.NewField(true, feishu.NewLarkMd("**分支**\n"+e.Branch)),
+			feishu.NewField(true, feishu.NewLarkMd("**触发者**\n"+e.Actor)),
+		)
+	if len(e.Commits) > 0 {
+		builder.Div(feishu.NewPlainText(shortID(e.Commits[0].ID) + " " + firstLine(e.Commits[0].Message)))
+	}
+	builder.Action(feishu.NewButton("查看运行详情", feishu.ButtonPrimary).WithURL(e.URL))
+	return builder.Build()
+}
+
+func (e *Event) releaseCard() (*feishu.Message, error) {
+	builder := feishu.NewCardBuilder().
+		Header(fmt.Sprintf("[%s] 发布 %s", e.Repo, e.Title), "violet").
+		Markdown(fmt.Sprintf("**%s** 发布了 %s", e.Actor, e.Branch))
+	if body := truncate(e.Body, maxBodyRunes); body != "" {
+		builder.Div(feishu.NewPlainText(body))
+	}
+	builder.Action(feishu.NewButton("查看发布", feishu.ButtonPrimary).WithURL(e.URL))
+	return builder.Build()
+}
+
+func shortID(id string) string {
+	if len(id) > 7 {
+		return id[:7]
+	}
+	return id
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n]) + "…"
+}
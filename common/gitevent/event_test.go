@@ -0,0 +1,123 @@
+package gitevent
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+func messageJSON(t *testing.T, message *feishu.Message) string {
+	t.Helper()
+	if err := message.Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	data, err := json.Marshal(message.Content)
+	if err != nil {
+		t.Fatalf("marshal message failed: %v", err)
+	}
+	return string(data)
+}
+
+func TestEventMessage(t *testing.T) {
+	t.Run("推送", func(t *testing.T) {
+		e := &Event{
+			Kind:   KindPush,
+			Repo:   "octo/app",
+			Actor:  "alice",
+			Branch: "main",
+			Commits: []Commit{
+				{ID: "1234567890abcdef", Message: "修复登录\n\n详细说明", Author: "alice", URL: "https://example.com/c/1"},
+				{ID: "abcdef1234567890", Message: "更新文档", Author: "bob", URL: "https://example.com/c/2"},
+				{ID: "fedcba0987654321", Message: "重构", Author: "bob"},
+			},
+			TotalCommits: 25,
+			CompareURL:   "https://example.com/compare",
+		}
+		message, err := e.Message(2)
+		if err != nil {
+			t.Fatalf("Message() error: %v", err)
+		}
+		if message.MsgType != feishu.MessageTypeRichText {
+			t.Fatalf("msg_type = %q", message.MsgType)
+		}
+		data := messageJSON(t, message)
+		for _, want := range []string{
+			"[octo/app] alice 推送了 25 个提交到 main",
+			`"text":"1234567"`,
+			"修复登录",
+			" - bob",
+			"……还有 23 个提交",
+			"查看对比",
+		} {
+			if !strings.Contains(data, want) {
+				t.Errorf("post should contain %q:\n%s", want, data)
+			}
+		}
+		if strings.Contains(data, "详细说明") || strings.Contains(data, "重构") {
+			t.Errorf("post should only show the first line of the first 2 commits:\n%s", data)
+		}
+		if strings.Contains(data, " - alice") {
+			t.Error("author equal to pusher should be omitted")
+		}
+	})
+
+	t.Run("合并 PR", func(t *testing.T) {
+		e := &Event{
+			Kind: KindPullRequest, Action: ActionMerged, Repo: "octo/app", Actor: "alice",
+			Number: 42, Title: "新增导出", Body: strings.Repeat("很长的描述", 100), URL: "https://example.com/pull/42",
+			Branch: "feature", TargetBranch: "main",
+		}
+		message, err := e.Message(DefaultMaxCommits)
+		if err != nil {
+			t.Fatalf("Message() error: %v", err)
+		}
+		card := message.Content.(*feishu.InteractiveContent)
+		if card.Header.Template != "purple" || card.Header.Title.Content != "[octo/app] PR #42 新增导出" {
+			t.Errorf("header = %q, %q", card.Header.Template, card.Header.Title.Content)
+		}
+		data := messageJSON(t, message)
+		for _, want := range []string{"**alice** 合并了 PR", "feature → main", "…", "https://example.com/pull/42"} {
+			if !strings.Contains(data, want) {
+				t.Errorf("card should contain %q:\n%s", want, data)
+			}
+		}
+	})
+
+	t.Run("流水线失败", func(t *testing.T) {
+		e := &Event{
+			Kind: KindWorkflowRun, Action: ActionCompleted, Repo: "octo/app", Actor: "alice",
+			Number: 7, Title: "CI", URL: "https://example.com/runs/7", Branch: "main",
+			Conclusion: ConclusionFailure, Commits: []Commit{{ID: "1234567890", Message: "修复登录"}},
+		}
+		message, err := e.Message(DefaultMaxCommits)
+		if err != nil {
+			t.Fatalf("Message() error: %v", err)
+		}
+		card := message.Content.(*feishu.InteractiveContent)
+		if card.Header.Template != "red" || card.Header.Title.Content != "[octo/app] CI #7 失败" {
+			t.Errorf("header = %q, %q", card.Header.Template, card.Header.Title.Content)
+		}
+		if data := messageJSON(t, message); !strings.Contains(data, "1234567 修复登录") {
+			t.Errorf("card should contain head commit:\n%s", data)
+		}
+	})
+
+	t.Run("发布", func(t *testing.T) {
+		e := &Event{Kind: KindRelease, Action: ActionPublished, Repo: "octo/app", Actor: "alice", Title: "v1.2.0", Branch: "v1.2.0", URL: "https://example.com/releases/v1.2.0"}
+		message, err := e.Message(DefaultMaxCommits)
+		if err != nil {
+			t.Fatalf("Message() error: %v", err)
+		}
+		if data := messageJSON(t, message); !strings.Contains(data, "[octo/app] 发布 v1.2.0") {
+			t.Errorf("card title mismatch:\n%s", data)
+		}
+	})
+
+	t.Run("未知类型", func(t *testing.T) {
+		if _, err := (&Event{Kind: "star"}).Message(DefaultMaxCommits); err == nil {
+			t.Error("expected error for unknown kind")
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package gitevent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrSignatureInvalid = errors.New("gitevent: signature invalid")
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubRepository struct {
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url"`
+}
+
+type githubCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Author  struct {
+		Name     string `json:"name"`
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+type githubPayload struct {
+	Action     string           `json:"action"`
+	Repository githubRepository `json:"repository"`
+	Sender     githubUser       `json:"sender"`
+
+	// push
+	Ref     string         `json:"ref"`
+	Compare string         `json:"compare"`
+	Forced  bool           `json:"forced"`
+	Deleted bool           `json:"deleted"`
+	Commits []githubCommit `json:"commits"`
+	Pusher  struct {
+		Name string `json:"name"`
+	} `json:"pusher"`
+
+	PullRequest *struct {
+		Number  int        `json:"number"`
+		Title   string     `json:"title"`
+		Body    string     `json:"body"`
+		HTMLURL string     `json:"html_url"`
+		Merged  bool       `json:"merged"`
+		User    githubUser `json:"user"`
+		Head    struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	} `json:"pull_request"`
+
+	Issue *struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+	} `json:"issue"`
+
+	WorkflowRun *struct {
+		Name       string       `json:"name"`
+		RunNumber  int          `json:"run_number"`
+		HeadBranch string       `json:"head_branch"`
+		HeadSHA    string       `json:"head_sha"`
+		Conclusion string       `json:"conclusion"`
+		HTMLURL    string       `json:"html_url"`
+		Actor      githubUser   `json:"actor"`
+		HeadCommit githubCommit `json:"head_commit"`
+	} `json:"workflow_run"`
+
+	Release *struct {
+		TagName    string     `json:"tag_name"`
+		Name       string     `json:"name"`
+		Body       string     `json:"body"`
+		HTMLURL    string     `json:"html_url"`
+		Prerelease bool       `json:"prerelease"`
+		Author     githubUser `json:"author"`
+	} `json:"release"`
+}
+
+// VerifyGitHubSignature 校验 X-Hub-Signature-256 请求头，格式为 sha256=<hex>
+func VerifyGitHubSignature(secret string, body []byte, signature string) error {
+	hexSum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrSignatureInvalid
+	}
+	got, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), got) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// ParseGitHub 解析 GitHub webhook，eventType 为 X-GitHub-Event 请求头的值
+func ParseGitHub(eventType string, body []byte) (*Event, error) {
+	switch eventType {
+	case KindPush, KindPullRequest, KindIssue, KindWorkflowRun, KindRelease:
+	default:
+		return nil, ErrIgnored
+	}
+
+	var p githubPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("gitevent: decode github %s event failed: %w", eventType, err)
+	}
+
+	e := &Event{
+		Provider: ProviderGitHub,
+		Kind:     eventType,
+		Action:   p.Action,
+		Repo:     p.Repository.FullName,
+		RepoURL:  p.Repository.HTMLURL,
+		Actor:    p.Sender.Login,
+	}
+
+	switch eventType {
+	case KindPush:
+		// 标签推送没有提交列表，由 release 事件通知，与 GitLab 的 tag_push 一样忽略
+		if p.Deleted || strings.HasPrefix(p.Ref, "refs/tags/") {
+			return nil, ErrIgnored
+		}
+		e.Branch = refName(p.Ref)
+		e.CompareURL = p.Compare
+		e.Forced = p.Forced
+		e.TotalCommits = len(p.Commits)
+		if p.Pusher.Name != "" {
+			e.Actor = p.Pusher.Name
+		}
+		for _, c := range p.Commits {
+			e.Commits = append(e.Commits, Commit{ID: c.ID, Message: c.Message, Author: firstNonEmpty(c.Author.Username, c.Author.Name), URL: c.URL})
+		}
+
+	case KindPullRequest:
+		pr := p.PullRequest
+		if pr == nil {
+			return nil, fmt.Errorf("gitevent: github pull_request event has no pull_request")
+		}
+		if p.Action == ActionClosed && pr.Merged {
+			e.Action = ActionMerged
+		}
+		if !notableItemActions[e.Action] {
+			return nil, ErrIgnored
+		}
+		e.Number, e.Title, e.Body, e.URL = pr.Number, pr.Title, pr.Body, pr.HTMLURL
+		e.Branch, e.TargetBranch = pr.Head.Ref, pr.Base.Ref
+
+	case KindIssue:
+		issue := p.Issue
+		if issue == nil {
+			return nil, fmt.Errorf("gitevent: github issues event has no issue")
+		}
+		if !notableItemActions[e.Action] {
+			return nil, ErrIgnored
+		}
+		e.Number, e.Title, e.Body, e.URL = issue.Number, issue.Title, issue.Body, issue.HTMLURL
+
+	case KindWorkflowRun:
+		run := p.WorkflowRun
+		if run == nil {
+			return nil, fmt.Errorf("gitevent: github workflow_run event has no workflow_run")
+		}
+		if p.Action != ActionCompleted || run.Conclusion == "skipped" {
+			return nil, ErrIgnored
+		}
+		e.Number, e.Title, e.URL = run.RunNumber, run.Name, run.HTMLURL
+		e.Branch, e.Conclusion = run.HeadBranch, run.Conclusion
+		e.Actor = firstNonEmpty(run.Actor.Login, e.Actor)
+		e.Commits = []Commit{{ID: firstNonEmpty(run.HeadCommit.ID, run.HeadSHA), Message: run.HeadCommit.Message}}
+
+	case KindRelease:
+		release := p.Release
+		if release == nil {
+			return nil, fmt.Errorf("gitevent: github release event has no release")
+		}
+		if p.Action != ActionPublished {
+			return nil, ErrIgnored
+		}
+		e.Title = firstNonEmpty(release.Name, release.TagName)
+		e.Branch, e.Body, e.URL = release.TagName, release.Body, release.HTMLURL
+		e.Actor = firstNonEmpty(release.Author.Login, e.Actor)
+	}
+	return e, nil
+}
+
+// notableItemActions 是 PR 和 issue 需要通知的动作，其余动作（编辑、打标签、同步等）会被忽略
+var notableItemActions = map[string]bool{
+	ActionOpened:   true,
+	ActionClosed:   true,
+	ActionReopened: true,
+	ActionMerged:   true,
+}
+
+func refName(ref string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			return name
+		}
+	}
+	return ref
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
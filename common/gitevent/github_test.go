@@ -0,0 +1,162 @@
+package gitevent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+const githubPushPayload = `{
+  "ref": "refs/heads/main",
+  "compare": "https://github.com/octo/app/compare/aaa...bbb",
+  "forced": false,
+  "deleted": false,
+  "repository": {"full_name": "octo/app", "html_url": "https://github.com/octo/app"},
+  "pusher": {"name": "alice"},
+  "sender": {"login": "alice"},
+  "commits": [
+    {"id": "1111111aaaa", "message": "修复登录", "url": "https://github.com/octo/app/commit/1111111aaaa", "author": {"name": "Alice", "username": "alice"}},
+    {"id": "2222222bbbb", "message": "更新文档", "url": "https://github.com/octo/app/commit/2222222bbbb", "author": {"name": "Bob"}}
+  ]
+}`
+
+const githubPullRequestPayload = `{
+  "action": "closed",
+  "repository": {"full_name": "octo/app", "html_url": "https://github.com/octo/app"},
+  "sender": {"login": "bob"},
+  "pull_request": {
+    "number": 42,
+    "title": "新增导出",
+    "body": "导出为 CSV",
+    "html_url": "https://github.com/octo/app/pull/42",
+    "merged": true,
+    "user": {"login": "alice"},
+    "head": {"ref": "feature"},
+    "base": {"ref": "main"}
+  }
+}`
+
+const githubWorkflowRunPayload = `{
+  "action": "completed",
+  "repository": {"full_name": "octo/app", "html_url": "https://github.com/octo/app"},
+  "sender": {"login": "bot"},
+  "workflow_run": {
+    "name": "CI",
+    "run_number": 7,
+    "head_branch": "main",
+    "head_sha": "3333333cccc",
+    "conclusion": "failure",
+    "html_url": "https://github.com/octo/app/actions/runs/1",
+    "actor": {"login": "alice"},
+    "head_commit": {"id": "3333333cccc", "message": "修复登录"}
+  }
+}`
+
+func githubSignature(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	body := []byte(githubPushPayload)
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{"正确签名", githubSignature("secret", githubPushPayload), false},
+		{"错误密钥", githubSignature("other", githubPushPayload), true},
+		{"缺少前缀", githubSignature("secret", githubPushPayload)[len("sha256="):], true},
+		{"非十六进制", "sha256=zz", true},
+		{"空签名", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyGitHubSignature("secret", body, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyGitHubSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseGitHub(t *testing.T) {
+	t.Run("推送", func(t *testing.T) {
+		e, err := ParseGitHub("push", []byte(githubPushPayload))
+		if err != nil {
+			t.Fatalf("ParseGitHub() error: %v", err)
+		}
+		if e.Provider != ProviderGitHub || e.Repo != "octo/app" || e.Branch != "main" || e.Actor != "alice" {
+			t.Errorf("event = %+v", e)
+		}
+		if len(e.Commits) != 2 || e.Commits[1].Author != "Bob" || e.TotalCommits != 2 {
+			t.Errorf("commits = %+v", e.Commits)
+		}
+	})
+
+	t.Run("合并的 PR", func(t *testing.T) {
+		e, err := ParseGitHub("pull_request", []byte(githubPullRequestPayload))
+		if err != nil {
+			t.Fatalf("ParseGitHub() error: %v", err)
+		}
+		if e.Action != ActionMerged || e.Number != 42 || e.Actor != "bob" || e.Branch != "feature" || e.TargetBranch != "main" {
+			t.Errorf("event = %+v", e)
+		}
+	})
+
+	t.Run("流水线", func(t *testing.T) {
+		e, err := ParseGitHub("workflow_run", []byte(githubWorkflowRunPayload))
+		if err != nil {
+			t.Fatalf("ParseGitHub() error: %v", err)
+		}
+		if e.Conclusion != ConclusionFailure || e.Actor != "alice" || e.Title != "CI" || e.Number != 7 {
+			t.Errorf("event = %+v", e)
+		}
+	})
+
+	t.Run("发布", func(t *testing.T) {
+		e, err := ParseGitHub("release", []byte(`{
+		  "action": "published",
+		  "repository": {"full_name": "octo/app"},
+		  "sender": {"login": "alice"},
+		  "release": {"tag_name": "v1.2.0", "name": "", "html_url": "https://github.com/octo/app/releases/v1.2.0"}
+		}`))
+		if err != nil {
+			t.Fatalf("ParseGitHub() error: %v", err)
+		}
+		if e.Title != "v1.2.0" || e.Branch != "v1.2.0" {
+			t.Errorf("event = %+v", e)
+		}
+	})
+
+	ignored := []struct {
+		name      string
+		eventType string
+		body      string
+	}{
+		{"ping", "ping", `{"zen": "Keep it simple."}`},
+		{"删除分支", "push", `{"ref": "refs/heads/old", "deleted": true}`},
+		{"标签推送", "push", `{"ref": "refs/tags/v1.0", "commits": []}`},
+		{"PR 打标签", "pull_request", `{"action": "labeled", "pull_request": {"number": 1}}`},
+		{"issue 指派", "issues", `{"action": "assigned", "issue": {"number": 1}}`},
+		{"流水线进行中", "workflow_run", `{"action": "requested", "workflow_run": {"name": "CI"}}`},
+		{"预发布草稿", "release", `{"action": "created", "release": {"tag_name": "v1"}}`},
+	}
+	for _, tt := range ignored {
+		t.Run("忽略"+tt.name, func(t *testing.T) {
+			if _, err := ParseGitHub(tt.eventType, []byte(tt.body)); !errors.Is(err, ErrIgnored) {
+				t.Errorf("ParseGitHub() error = %v, want ErrIgnored", err)
+			}
+		})
+	}
+
+	t.Run("无效 JSON", func(t *testing.T) {
+		_, err := ParseGitHub("push", []byte("{"))
+		if err == nil || errors.Is(err, ErrIgnored) {
+			t.Errorf("ParseGitHub() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package gitevent
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type gitlabUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+type gitlabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+}
+
+type gitlabCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Author  struct {
+		Name string `json:"name"`
+	} `json:"author"`
+}
+
+type gitlabPayload struct {
+	ObjectKind string        `json:"object_kind"`
+	User       gitlabUser    `json:"user"`
+	Project    gitlabProject `json:"project"`
+
+	// push
+	Ref               string         `json:"ref"`
+	Before            string         `json:"before"`
+	After             string         `json:"after"`
+	UserUsername      string         `json:"user_username"`
+	UserName          string         `json:"user_name"`
+	Commits           []gitlabCommit `json:"commits"`
+	TotalCommitsCount int            `json:"total_commits_count"`
+
+	// merge_request、issue 和 pipeline
+	ObjectAttributes struct {
+		ID           int    `json:"id"`
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		URL          string `json:"url"`
+		Action       string `json:"action"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		Ref          string `json:"ref"`
+		Status       string `json:"status"`
+		Name         string `json:"name"`
+	} `json:"object_attributes"`
+	Commit *gitlabCommit `json:"commit"`
+
+	// release
+	Action      string `json:"action"`
+	Name        string `json:"name"`
+	Tag         string `json:"tag"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// VerifyGitLabToken 校验 X-Gitlab-Token 请求头
+func VerifyGitLabToken(token, header string) error {
+	if subtle.ConstantTimeCompare([]byte(token), []byte(header)) != 1 {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+var gitlabActions = map[string]string{
+	"open":   ActionOpened,
+	"close":  ActionClosed,
+	"reopen": ActionReopened,
+	"merge":  ActionMerged,
+}
+
+var gitlabConclusions = map[string]string{
+	"success":  ConclusionSuccess,
+	"failed":   ConclusionFailure,
+	"canceled": ConclusionCancelled,
+}
+
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// ParseGitLab 解析 GitLab webhook，事件类型取自请求体中的 object_kind
+func ParseGitLab(body []byte) (*Event, error) {
+	var p gitlabPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("gitevent: decode gitlab event failed: %w", err)
+	}
+
+	e := &Event{
+		Provider: ProviderGitLab,
+		Repo:     p.Project.PathWithNamespace,
+		RepoURL:  p.Project.WebURL,
+		Actor:    firstNonEmpty(p.User.Username, p.User.Name),
+	}
+	attrs := p.ObjectAttributes
+
+	switch p.ObjectKind {
+	case "push":
+		if p.After == zeroSHA {
+			return nil, ErrIgnored
+		}
+		e.Kind = KindPush
+		e.Actor = firstNonEmpty(p.UserUsername, p.UserName)
+		e.Branch = refName(p.Ref)
+		e.TotalCommits = p.TotalCommitsCount
+		if p.Before != zeroSHA {
+			e.CompareURL = fmt.Sprintf("%s/-/compare/%s...%s", p.Project.WebURL, p.Before, p.After)
+		}
+		for _, c := range p.Commits {
+			e.Commits = append(e.Commits, Commit{ID: c.ID, Message: c.Message, Author: c.Author.Name, URL: c.URL})
+		}
+
+	case "merge_request", "issue":
+		e.Kind = KindPullRequest
+		if p.ObjectKind == "issue" {
+			e.Kind = KindIssue
+		}
+		e.Action = gitlabActions[attrs.Action]
+		if e.Action == "" {
+			return nil, ErrIgnored
+		}
+		e.Number, e.Title, e.Body, e.URL = attrs.IID, attrs.Title, attrs.Description, attrs.URL
+		e.Branch, e.TargetBranch = attrs.SourceBranch, attrs.TargetBranch
+
+	case "pipeline":
+		conclusion, ok := gitlabConclusions[attrs.Status]
+		if !ok {
+			return nil, ErrIgnored
+		}
+		e.Kind, e.Action, e.Conclusion = KindWorkflowRun, ActionCompleted, conclusion
+		e.Number, e.Branch = attrs.ID, attrs.Ref
+		e.Title = firstNonEmpty(attrs.Name, "Pipeline")
+		e.URL = fmt.Sprintf("%s/-/pipelines/%d", p.Project.WebURL, attrs.ID)
+		if p.Commit != nil {
+			e.Commits = []Commit{{ID: p.Commit.ID, Message: p.Commit.Message, Author: p.Commit.Author.Name, URL: p.Commit.URL}}
+		}
+
+	case "release":
+		if p.Action != "create" {
+			return nil, ErrIgnored
+		}
+		e.Kind, e.Action = KindRelease, ActionPublished
+		e.Title = firstNonEmpty(p.Name, p.Tag)
+		e.Branch, e.Body = p.Tag, p.Description
+		e.URL = firstNonEmpty(p.URL, strings.TrimRight(p.Project.WebURL, "/")+"/-/releases/"+p.Tag)
+
+	default:
+		return nil, ErrIgnored
+	}
+	return e, nil
+}
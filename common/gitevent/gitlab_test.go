@@ -0,0 +1,127 @@
+package gitevent
+
+import (
+	"errors"
+	"testing"
+)
+
+const gitlabPushPayload = `{
+  "object_kind": "push",
+  "before": "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+  "after": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+  "ref": "refs/heads/main",
+  "user_name": "Alice",
+  "user_username": "alice",
+  "project": {"path_with_namespace": "group/app", "web_url": "https://gitlab.example.com/group/app"},
+  "commits": [
+    {"id": "bbbbbbbbbbbb", "message": "修复登录\n", "url": "https://gitlab.example.com/group/app/-/commit/bbbbbbbbbbbb", "author": {"name": "Alice"}}
+  ],
+  "total_commits_count": 30
+}`
+
+const gitlabMergeRequestPayload = `{
+  "object_kind": "merge_request",
+  "user": {"name": "Bob", "username": "bob"},
+  "project": {"path_with_namespace": "group/app", "web_url": "https://gitlab.example.com/group/app"},
+  "object_attributes": {
+    "iid": 5,
+    "title": "新增导出",
+    "description": "导出为 CSV",
+    "url": "https://gitlab.example.com/group/app/-/merge_requests/5",
+    "action": "merge",
+    "source_branch": "feature",
+    "target_branch": "main"
+  }
+}`
+
+const gitlabPipelinePayload = `{
+  "object_kind": "pipeline",
+  "user": {"name": "Alice", "username": "alice"},
+  "project": {"path_with_namespace": "group/app", "web_url": "https://gitlab.example.com/group/app"},
+  "object_attributes": {"id": 99, "ref": "main", "status": "canceled"},
+  "commit": {"id": "cccccccccccc", "message": "修复登录", "author": {"name": "Alice"}}
+}`
+
+func TestVerifyGitLabToken(t *testing.T) {
+	if err := VerifyGitLabToken("token", "token"); err != nil {
+		t.Errorf("VerifyGitLabToken() error: %v", err)
+	}
+	for _, header := range []string{"", "tok", "token2"} {
+		if err := VerifyGitLabToken("token", header); !errors.Is(err, ErrSignatureInvalid) {
+			t.Errorf("VerifyGitLabToken(%q) error = %v", header, err)
+		}
+	}
+}
+
+func TestParseGitLab(t *testing.T) {
+	t.Run("推送", func(t *testing.T) {
+		e, err := ParseGitLab([]byte(gitlabPushPayload))
+		if err != nil {
+			t.Fatalf("ParseGitLab() error: %v", err)
+		}
+		if e.Provider != ProviderGitLab || e.Kind != KindPush || e.Actor != "alice" || e.Branch != "main" || e.TotalCommits != 30 {
+			t.Errorf("event = %+v", e)
+		}
+		want := "https://gitlab.example.com/group/app/-/compare/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa...bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		if e.CompareURL != want {
+			t.Errorf("compare url = %q", e.CompareURL)
+		}
+	})
+
+	t.Run("合并 MR", func(t *testing.T) {
+		e, err := ParseGitLab([]byte(gitlabMergeRequestPayload))
+		if err != nil {
+			t.Fatalf("ParseGitLab() error: %v", err)
+		}
+		if e.Kind != KindPullRequest || e.Action != ActionMerged || e.Number != 5 || e.Actor != "bob" || e.TargetBranch != "main" {
+			t.Errorf("event = %+v", e)
+		}
+	})
+
+	t.Run("流水线取消", func(t *testing.T) {
+		e, err := ParseGitLab([]byte(gitlabPipelinePayload))
+		if err != nil {
+			t.Fatalf("ParseGitLab() error: %v", err)
+		}
+		if e.Kind != KindWorkflowRun || e.Conclusion != ConclusionCancelled || e.Number != 99 {
+			t.Errorf("event = %+v", e)
+		}
+		if e.URL != "https://gitlab.example.com/group/app/-/pipelines/99" {
+			t.Errorf("url = %q", e.URL)
+		}
+	})
+
+	t.Run("发布", func(t *testing.T) {
+		e, err := ParseGitLab([]byte(`{
+		  "object_kind": "release",
+		  "action": "create",
+		  "name": "",
+		  "tag": "v1.2.0",
+		  "project": {"path_with_namespace": "group/app", "web_url": "https://gitlab.example.com/group/app"}
+		}`))
+		if err != nil {
+			t.Fatalf("ParseGitLab() error: %v", err)
+		}
+		if e.Action != ActionPublished || e.Title != "v1.2.0" || e.URL != "https://gitlab.example.com/group/app/-/releases/v1.2.0" {
+			t.Errorf("event = %+v", e)
+		}
+	})
+
+	ignored := []struct {
+		name string
+		body string
+	}{
+		{"标签推送", `{"object_kind": "tag_push"}`},
+		{"删除分支", `{"object_kind": "push", "after": "0000000000000000000000000000000000000000"}`},
+		{"MR 更新", `{"object_kind": "merge_request", "object_attributes": {"action": "update"}}`},
+		{"流水线运行中", `{"object_kind": "pipeline", "object_attributes": {"status": "running"}}`},
+		{"发布更新", `{"object_kind": "release", "action": "update"}`},
+	}
+	for _, tt := range ignored {
+		t.Run("忽略"+tt.name, func(t *testing.T) {
+			if _, err := ParseGitLab([]byte(tt.body)); !errors.Is(err, ErrIgnored) {
+				t.Errorf("ParseGitLab() error = %v, want ErrIgnored", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package gitevent
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+const DefaultMaxBodyBytes = 4 << 20
+
+type Option func(*Handler)
+
+// WithGitHubSecret 设置 GitHub webhook 的 secret，未设置时拒绝所有 GitHub 请求
+func WithGitHubSecret(secret string) Option {
+	return func(h *Handler) {
+		h.githubSecret = secret
+	}
+}
+
+// WithGitLabToken 设置 GitLab webhook 的 secret token，未设置时拒绝所有 GitLab 请求
+func WithGitLabToken(token string) Option {
+	return func(h *Handler) {
+		h.gitlabToken = token
+	}
+}
+
+// WithMaxCommits 限制推送消息中列出的提交数量
+func WithMaxCommits(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxCommits = n
+		}
+	}
+}
+
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
+}
+
+func WithErrorLog(logger *log.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// Handler 接收 GitHub 和 GitLab 的 webhook，校验签名后把事件转发到飞书。
+// 来源根据 X-GitHub-Event 或 X-Gitlab-Event 请求头判断，同一个地址可以同时配置给两者
+type Handler struct {
+	sender       feishu.Sender
+	githubSecret string
+	gitlabToken  string
+	maxCommits   int
+	maxBodyBytes int64
+	logger       *log.Logger
+}
+
+func NewHandler(sender feishu.Sender, opts ...Option) *Handler {
+	h := &Handler{
+		sender:       sender,
+		maxCommits:   DefaultMaxCommits,
+		maxBodyBytes: DefaultMaxBodyBytes,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "read body failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	event, status, err := h.parse(r.Header, body)
+	if err != nil {
+		if errors.Is(err, ErrIgnored) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	message, err := event.Message(h.maxCommits)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err := h.sender.SendMessageContext(r.Context(), message); err != nil {
+		if h.logger != nil {
+			h.logger.Printf("gitevent: send %s %s event of %s failed: %v", event.Provider, event.Kind, event.Repo, err)
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// parse 校验签名并解析事件，失败时同时返回应答的状态码
+func (h *Handler) parse(header http.Header, body []byte) (*Event, int, error) {
+	var event *Event
+	var err error
+
+	switch {
+	case header.Get("X-GitHub-Event") != "":
+		if h.githubSecret == "" {
+			return nil, http.StatusForbidden, errors.New("gitevent: github webhook is not configured")
+		}
+		if err := VerifyGitHubSignature(h.githubSecret, body, header.Get("X-Hub-Signature-256")); err != nil {
+			return nil, http.StatusUnauthorized, err
+		}
+		event, err = ParseGitHub(header.Get("X-GitHub-Event"), body)
+
+	case header.Get("X-Gitlab-Event") != "":
+		if h.gitlabToken == "" {
+			return nil, http.StatusForbidden, errors.New("gitevent: gitlab webhook is not configured")
+		}
+		if err := VerifyGitLabToken(h.gitlabToken, header.Get("X-Gitlab-Token")); err != nil {
+			return nil, http.StatusUnauthorized, err
+		}
+		event, err = ParseGitLab(body)
+
+	default:
+		return nil, http.StatusBadRequest, errors.New("gitevent: missing X-GitHub-Event or X-Gitlab-Event header")
+	}
+
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return event, http.StatusOK, nil
+}
@@ -0,0 +1,111 @@
+package gitevent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+	"github.com/straubel/feishu-webhook/common/feishu/feishutest"
+)
+
+type senderFunc func(ctx context.Context, message *feishu.Message) error
+
+func (f senderFunc) SendMessageContext(ctx context.Context, message *feishu.Message) error {
+	return f(ctx, message)
+}
+
+func githubRequest(event, secret, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", githubSignature(secret, body))
+	return req
+}
+
+func gitlabRequest(event, token, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", event)
+	req.Header.Set("X-Gitlab-Token", token)
+	return req
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("通过 SDK 发送", func(t *testing.T) {
+		server := feishutest.NewServer(feishutest.WithSecret("feishu-secret"))
+		defer server.Close()
+
+		handler := NewHandler(feishu.New(server.URL, "feishu-secret"), WithGitHubSecret("gh"), WithGitLabToken("gl"))
+		for _, req := range []*http.Request{
+			githubRequest("push", "gh", githubPushPayload),
+			githubRequest("pull_request", "gh", githubPullRequestPayload),
+			gitlabRequest("Pipeline Hook", "gl", gitlabPipelinePayload),
+		} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+			}
+		}
+
+		messages := server.Messages()
+		if len(messages) != 3 {
+			t.Fatalf("got %d messages", len(messages))
+		}
+		want := []feishu.MessageType{feishu.MessageTypeRichText, feishu.MessageTypeInteractive, feishu.MessageTypeInteractive}
+		for i, message := range messages {
+			if message.MsgType != want[i] {
+				t.Errorf("messages[%d].msg_type = %q, want %q", i, message.MsgType, want[i])
+			}
+		}
+	})
+
+	ok := senderFunc(func(ctx context.Context, message *feishu.Message) error { return nil })
+	failing := senderFunc(func(ctx context.Context, message *feishu.Message) error { return errors.New("down") })
+	tests := []struct {
+		name    string
+		sender  feishu.Sender
+		opts    []Option
+		request func() *http.Request
+		want    int
+	}{
+		{"只接受 POST", ok, nil, func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/hooks", nil)
+		}, http.StatusMethodNotAllowed},
+		{"缺少事件头", ok, nil, func() *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader("{}"))
+		}, http.StatusBadRequest},
+		{"未配置 GitHub", ok, []Option{WithGitLabToken("gl")}, func() *http.Request {
+			return githubRequest("push", "gh", githubPushPayload)
+		}, http.StatusForbidden},
+		{"GitHub 签名错误", ok, []Option{WithGitHubSecret("gh")}, func() *http.Request {
+			return githubRequest("push", "wrong", githubPushPayload)
+		}, http.StatusUnauthorized},
+		{"GitLab token 错误", ok, []Option{WithGitLabToken("gl")}, func() *http.Request {
+			return gitlabRequest("Push Hook", "wrong", gitlabPushPayload)
+		}, http.StatusUnauthorized},
+		{"忽略 ping", ok, []Option{WithGitHubSecret("gh")}, func() *http.Request {
+			return githubRequest("ping", "gh", `{"zen":"hi"}`)
+		}, http.StatusNoContent},
+		{"无效 JSON", ok, []Option{WithGitHubSecret("gh")}, func() *http.Request {
+			return githubRequest("push", "gh", "{")
+		}, http.StatusBadRequest},
+		{"请求体过大", ok, []Option{WithGitHubSecret("gh"), WithMaxBodyBytes(16)}, func() *http.Request {
+			return githubRequest("push", "gh", githubPushPayload)
+		}, http.StatusRequestEntityTooLarge},
+		{"发送失败", failing, []Option{WithGitHubSecret("gh")}, func() *http.Request {
+			return githubRequest("push", "gh", githubPushPayload)
+		}, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewHandler(tt.sender, tt.opts...).ServeHTTP(rec, tt.request())
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d, body = %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+// Package grafana 接收 Grafana 告警的 webhook 联络点通知，并渲染为飞书卡片
+package grafana
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+	"github.com/straubel/feishu-webhook/common/internal/alertcard"
+)
+
+const (
+	DefaultMaxAlerts    = 10
+	DefaultMaxBodyBytes = 4 << 20
+
+	// DefaultTitleTemplate 优先使用 Grafana 生成的标题
+	DefaultTitleTemplate = `{{if .Title}}{{.Title}}{{else}}[{{upper .Status}}] {{index .CommonLabels "alertname"}}{{end}}`
+	// DefaultAlertTemplate 渲染单条告警的 lark_md 内容
+	DefaultAlertTemplate = `**{{index .Labels "alertname"}}**{{with .Annotations.summary}} {{.}}{{end}}
+{{with .Annotations.description}}{{.}}
+{{end}}{{with .ValueString}}{{.}}{{else}}{{range $name, $value := .Values}}{{$name}} = {{$value}}  {{end}}{{end}}`
+)
+
+var ErrNoAlerts = errors.New("grafana: payload has no alerts")
+
+// DefaultStateColors 是各状态对应的卡片标题颜色，同时覆盖 state（alerting、ok 等）和 status（firing、resolved）
+var DefaultStateColors = map[string]string{
+	"alerting": "red",
+	"firing":   "red",
+	"ok":       "green",
+	"resolved": "green",
+	"pending":  "orange",
+	"no_data":  "grey",
+	"paused":   "grey",
+}
+
+// Payload 是 Grafana 统一告警 webhook 联络点的请求体
+type Payload struct {
+	Receiver          string            `json:"receiver"`
+	Status            string            `json:"status"`
+	OrgID             int64             `json:"orgId"`
+	Alerts            []Alert           `json:"alerts"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Title             string            `json:"title"`
+	State             string            `json:"state"`
+	Message           string            `json:"message"`
+}
+
+type Alert struct {
+	Status       string             `json:"status"`
+	Labels       map[string]string  `json:"labels"`
+	Annotations  map[string]string  `json:"annotations"`
+	StartsAt     time.Time          `json:"startsAt"`
+	EndsAt       time.Time          `json:"endsAt"`
+	Values       map[string]float64 `json:"values"`
+	ValueString  string             `json:"valueString"`
+	GeneratorURL string             `json:"generatorURL"`
+	Fingerprint  string             `json:"fingerprint"`
+	SilenceURL   string             `json:"silenceURL"`
+	DashboardURL string             `json:"dashboardURL"`
+	PanelURL     string             `json:"panelURL"`
+	ImageURL     string             `json:"imageURL"`
+}
+
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+type Option func(*Handler)
+
+// WithTitleTemplate 设置卡片标题模板，模板数据为 *Payload
+func WithTitleTemplate(text string) Option {
+	return func(h *Handler) {
+		h.title = h.parse("title", text)
+	}
+}
+
+// WithAlertTemplate 设置单条告警的 lark_md 模板，模板数据为 Alert
+func WithAlertTemplate(text string) Option {
+	return func(h *Handler) {
+		h.alert = h.parse("alert", text)
+	}
+}
+
+// WithStateColors 覆盖部分状态的标题颜色
+func WithStateColors(colors map[string]string) Option {
+	return func(h *Handler) {
+		for state, color := range colors {
+			h.colors[state] = color
+		}
+	}
+}
+
+func WithMaxAlerts(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxAlerts = n
+		}
+	}
+}
+
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
+}
+
+func WithErrorLog(logger *log.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// Handler 是 Grafana webhook 联络点，每次通知发送为一张卡片
+type Handler struct {
+	sender       feishu.Sender
+	title        *template.Template
+	alert        *template.Template
+	colors       map[string]string
+	maxAlerts    int
+	maxBodyBytes int64
+	logger       *log.Logger
+	// err 记录模板的解析错误，由 Err 和 Render 返回
+	err error
+}
+
+// NewHandler 创建 Handler，模板无法解析时通过 Err 返回错误，此时所有通知都会被拒绝
+func NewHandler(sender feishu.Sender, opts ...Option) *Handler {
+	h := &Handler{
+		sender:       sender,
+		title:        template.Must(template.New("title").Funcs(funcs).Parse(DefaultTitleTemplate)),
+		alert:        template.Must(template.New("alert").Funcs(funcs).Parse(DefaultAlertTemplate)),
+		colors:       make(map[string]string, len(DefaultStateColors)),
+		maxAlerts:    DefaultMaxAlerts,
+		maxBodyBytes: DefaultMaxBodyBytes,
+	}
+	for state, color := range DefaultStateColors {
+		h.colors[state] = color
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Err 返回自定义模板的解析错误
+func (h *Handler) Err() error {
+	return h.err
+}
+
+func (h *Handler) parse(name, text string) *template.Template {
+	t, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		if h.err == nil {
+			h.err = fmt.Errorf("grafana: parse %s template failed: %w", name, err)
+		}
+		return nil
+	}
+	return t
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.err != nil {
+		http.Error(w, h.err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var payload Payload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes)).Decode(&payload); err != nil {
+		http.Error(w, "invalid grafana payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// 没有任何告警的通知无法渲染出有内容的卡片，直接忽略
+	if len(payload.Alerts) == 0 && payload.TruncatedAlerts == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	message, err := h.Render(&payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err := h.sender.SendMessageContext(r.Context(), message); err != nil {
+		if h.logger != nil {
+			h.logger.Printf("grafana: send %s failed: %v", payload.GroupKey, err)
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Render 把通知渲染为卡片，标题颜色取决于 state，缺省时取决于 status
+func (h *Handler) Render(p *Payload) (*feishu.Message, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	if len(p.Alerts) == 0 && p.TruncatedAlerts == 0 {
+		return nil, ErrNoAlerts
+	}
+
+	title, err := execute(h.title, p)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := feishu.NewCardBuilder().
+		Config(feishu.CreateCardConfig(true)).
+		Header(strings.TrimSpace(title), h.color(p))
+
+	for i, alert := range p.Alerts {
+		if i == h.maxAlerts {
+			break
+		}
+		if i > 0 {
+			builder.Hr()
+		}
+
+		content, err := execute(h.alert, alert)
+		if err != nil {
+			return nil, err
+		}
+		builder.Div(feishu.NewLarkMd(strings.TrimSpace(content)), alertcard.LabelFields(alert.Labels, p.hideLabel)...)
+
+		if buttons := alertButtons(alert); len(buttons) > 0 {
+			builder.Action(buttons...)
+		}
+	}
+
+	hidden := p.TruncatedAlerts
+	if len(p.Alerts) > h.maxAlerts {
+		hidden += len(p.Alerts) - h.maxAlerts
+	}
+	alertcard.HiddenNote(builder, hidden)
+	if p.ExternalURL != "" {
+		builder.Note(feishu.NewLarkMd(fmt.Sprintf("联络点 %s · [Grafana](%s)", p.Receiver, p.ExternalURL)))
+	}
+	return builder.Build()
+}
+
+func (h *Handler) color(p *Payload) string {
+	if color, ok := h.colors[p.State]; ok && p.State != "" {
+		return color
+	}
+	return h.colors[p.Status]
+}
+
+// hideLabel 与 Alertmanager 一样只跳过已体现在标题中的分组标签，以及以 __ 开头的内部标签；
+// 单条告警时所有标签都是共有标签，不能按 CommonLabels 跳过
+func (p *Payload) hideLabel(name string) bool {
+	_, grouped := p.GroupLabels[name]
+	return grouped || strings.HasPrefix(name, "__")
+}
+
+// 卡片中的图片需要先上传获得 img_key，因此截图以链接按钮的形式展示
+func alertButtons(alert Alert) []feishu.CardAction {
+	var buttons []feishu.CardAction
+	for _, link := range []struct{ text, url string }{
+		{"查看面板", alert.PanelURL},
+		{"查看仪表盘", alert.DashboardURL},
+		{"查看截图", alert.ImageURL},
+		{"查看规则", alert.GeneratorURL},
+	} {
+		if link.url != "" {
+			buttons = append(buttons, feishu.NewButton(link.text, feishu.ButtonDefault).WithURL(link.url))
+		}
+	}
+	if alert.Status == "firing" && alert.SilenceURL != "" {
+		buttons = append(buttons, feishu.NewButton("静默", feishu.ButtonDanger).WithURL(alert.SilenceURL))
+	}
+	return buttons
+}
+
+func execute(t *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("grafana: render %s template failed: %w", t.Name(), err)
+	}
+	return buf.String(), nil
+}
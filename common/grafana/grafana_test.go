@@ -0,0 +1,219 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+	"github.com/straubel/feishu-webhook/common/feishu/feishutest"
+)
+
+const alertingPayload = `{
+  "receiver": "feishu",
+  "status": "firing",
+  "orgId": 1,
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighLatency", "grafana_folder": "api", "instance": "web-01", "__alert_rule_uid__": "abc"},
+      "annotations": {"summary": "P99 延迟过高", "description": "超过 500ms"},
+      "startsAt": "2024-05-01T08:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://grafana.example.com/alerting/grafana/abc/view",
+      "fingerprint": "f1",
+      "silenceURL": "http://grafana.example.com/alerting/silence/new?alertmanager=grafana",
+      "dashboardURL": "http://grafana.example.com/d/api",
+      "panelURL": "http://grafana.example.com/d/api?viewPanel=2",
+      "imageURL": "http://grafana.example.com/public/img/shot.png",
+      "values": {"B": 612.5},
+      "valueString": ""
+    }
+  ],
+  "groupLabels": {"alertname": "HighLatency"},
+  "commonLabels": {"alertname": "HighLatency", "grafana_folder": "api"},
+  "commonAnnotations": {},
+  "externalURL": "http://grafana.example.com/",
+  "version": "1",
+  "groupKey": "{}:{alertname=\"HighLatency\"}",
+  "truncatedAlerts": 0,
+  "title": "[FIRING:1] HighLatency (api)",
+  "state": "alerting",
+  "message": "**Firing**"
+}`
+
+type senderFunc func(ctx context.Context, message *feishu.Message) error
+
+func (f senderFunc) SendMessageContext(ctx context.Context, message *feishu.Message) error {
+	return f(ctx, message)
+}
+
+func decodePayload(t *testing.T) *Payload {
+	t.Helper()
+	var p Payload
+	if err := json.Unmarshal([]byte(alertingPayload), &p); err != nil {
+		t.Fatalf("decode payload failed: %v", err)
+	}
+	return &p
+}
+
+func render(t *testing.T, h *Handler, p *Payload) (*feishu.InteractiveContent, string) {
+	t.Helper()
+	message, err := h.Render(p)
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if err := message.Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	data, _ := json.Marshal(message.Content)
+	return message.Content.(*feishu.InteractiveContent), string(data)
+}
+
+func TestRender(t *testing.T) {
+	h := NewHandler(nil)
+
+	t.Run("默认模板", func(t *testing.T) {
+		card, data := render(t, h, decodePayload(t))
+		if card.Header.Title.Content != "[FIRING:1] HighLatency (api)" || card.Header.Template != "red" {
+			t.Errorf("header = %q %q", card.Header.Title.Content, card.Header.Template)
+		}
+		for _, want := range []string{
+			"**HighLatency** P99 延迟过高",
+			"超过 500ms",
+			"B = 612.5",
+			`**instance**\nweb-01`,
+			`**grafana_folder**\napi`,
+			"viewPanel=2",
+			"/d/api",
+			"shot.png",
+			"静默",
+		} {
+			if !strings.Contains(data, want) {
+				t.Errorf("card should contain %q:\n%s", want, data)
+			}
+		}
+		for _, unwanted := range []string{"__alert_rule_uid__", "**alertname**", "<no value>"} {
+			if strings.Contains(data, unwanted) {
+				t.Errorf("card should not contain %q", unwanted)
+			}
+		}
+	})
+
+	t.Run("状态颜色", func(t *testing.T) {
+		p := decodePayload(t)
+		p.State, p.Status = "ok", "resolved"
+		p.Alerts[0].Status = "resolved"
+		card, data := render(t, h, p)
+		if card.Header.Template != "green" {
+			t.Errorf("template = %q, want green", card.Header.Template)
+		}
+		if strings.Contains(data, "静默") {
+			t.Error("resolved alerts should not have a silence button")
+		}
+
+		p.State = ""
+		p.Status = "firing"
+		if card, _ := render(t, h, p); card.Header.Template != "red" {
+			t.Errorf("template from status = %q, want red", card.Header.Template)
+		}
+	})
+
+	t.Run("自定义模板和颜色", func(t *testing.T) {
+		h := NewHandler(nil,
+			WithTitleTemplate(`{{.Receiver}}: {{len .Alerts}} 条告警`),
+			WithAlertTemplate(`{{index .Labels "instance"}} {{.Status}}`),
+			WithStateColors(map[string]string{"alerting": "carmine"}),
+		)
+		card, data := render(t, h, decodePayload(t))
+		if card.Header.Title.Content != "feishu: 1 条告警" || card.Header.Template != "carmine" {
+			t.Errorf("header = %q %q", card.Header.Title.Content, card.Header.Template)
+		}
+		if !strings.Contains(data, "web-01 firing") {
+			t.Errorf("card = %s", data)
+		}
+	})
+
+	t.Run("模板错误", func(t *testing.T) {
+		invalid := NewHandler(nil, WithAlertTemplate("{{.Missing"))
+		if invalid.Err() == nil {
+			t.Error("Err() should report an invalid template")
+		}
+		if _, err := invalid.Render(decodePayload(t)); err == nil {
+			t.Error("Render() should fail for an invalid template")
+		}
+		rec := httptest.NewRecorder()
+		invalid.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/grafana", strings.NewReader(alertingPayload)))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d, want 500", rec.Code)
+		}
+
+		h := NewHandler(nil, WithAlertTemplate("{{.Missing}}"))
+		if _, err := h.Render(decodePayload(t)); err == nil {
+			t.Error("Render() should fail for an unknown field")
+		}
+	})
+
+	t.Run("没有告警", func(t *testing.T) {
+		p := decodePayload(t)
+		p.Alerts = nil
+		if _, err := h.Render(p); !errors.Is(err, ErrNoAlerts) {
+			t.Errorf("Render() error = %v, want ErrNoAlerts", err)
+		}
+	})
+
+	t.Run("限制告警数量", func(t *testing.T) {
+		p := decodePayload(t)
+		p.Alerts = append(p.Alerts, p.Alerts[0], p.Alerts[0])
+		h := NewHandler(nil, WithMaxAlerts(1))
+		if _, data := render(t, h, p); !strings.Contains(data, "还有 2 条告警未显示") {
+			t.Errorf("card = %s", data)
+		}
+	})
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("通过 Client 发送", func(t *testing.T) {
+		server := feishutest.NewServer(feishutest.WithSecret("secret"))
+		defer server.Close()
+
+		h := NewHandler(feishu.NewClient(server.URL, "secret"))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/grafana", strings.NewReader(alertingPayload)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+		}
+		if n := len(server.Messages()); n != 1 {
+			t.Errorf("messages = %d, want 1", n)
+		}
+	})
+
+	t.Run("错误响应", func(t *testing.T) {
+		failing := NewHandler(senderFunc(func(ctx context.Context, message *feishu.Message) error {
+			return errors.New("down")
+		}), WithMaxBodyBytes(4096))
+		tests := []struct {
+			name   string
+			method string
+			body   string
+			want   int
+		}{
+			{"只接受 POST", http.MethodGet, "", http.StatusMethodNotAllowed},
+			{"无效 JSON", http.MethodPost, "{", http.StatusBadRequest},
+			{"请求体过大", http.MethodPost, `{"title":"` + strings.Repeat("a", 4096) + `"}`, http.StatusBadRequest},
+			{"没有告警", http.MethodPost, `{"status":"firing","alerts":[]}`, http.StatusNoContent},
+			{"发送失败", http.MethodPost, alertingPayload, http.StatusBadGateway},
+		}
+		for _, tt := range tests {
+			rec := httptest.NewRecorder()
+			failing.ServeHTTP(rec, httptest.NewRequest(tt.method, "/grafana", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,37 @@
+// Package alertcard 是 alertmanager 和 grafana 共用的告警卡片渲染辅助函数
+package alertcard
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LabelFields 把标签按名称排序渲染为并排的字段，skip 返回 true 的标签不显示
+func LabelFields(labels map[string]string, skip func(name string) bool) []*feishu.CardField {
+	var fields []*feishu.CardField
+	for _, name := range SortedKeys(labels) {
+		if skip != nil && skip(name) {
+			continue
+		}
+		fields = append(fields, feishu.NewField(true, feishu.NewLarkMd(fmt.Sprintf("**%s**\n%s", name, labels[name]))))
+	}
+	return fields
+}
+
+// HiddenNote 在有告警因数量限制未显示时添加备注
+func HiddenNote(builder *feishu.CardBuilder, hidden int) {
+	if hidden > 0 {
+		builder.Note(feishu.NewPlainText(fmt.Sprintf("还有 %d 条告警未显示", hidden)))
+	}
+}
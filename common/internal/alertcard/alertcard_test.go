@@ -0,0 +1,34 @@
+package alertcard
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/straubel/feishu-webhook/common/feishu"
+)
+
+func TestLabelFields(t *testing.T) {
+	labels := map[string]string{"job": "api", "instance": "web-01", "__name__": "up"}
+	fields := LabelFields(labels, func(name string) bool { return strings.HasPrefix(name, "__") })
+	if len(fields) != 2 {
+		t.Fatalf("got %d fields, want 2", len(fields))
+	}
+	if fields[0].Text.Content != "**instance**\nweb-01" || fields[1].Text.Content != "**job**\napi" || !fields[0].IsShort {
+		t.Errorf("fields = %+v, %+v", fields[0], fields[1])
+	}
+}
+
+func TestHiddenNote(t *testing.T) {
+	builder := feishu.NewCardBuilder().Header("告警", "red").Markdown("内容")
+	HiddenNote(builder, 0)
+	HiddenNote(builder, 3)
+	message, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	data, _ := json.Marshal(message.Content)
+	if n := strings.Count(string(data), "未显示"); n != 1 || !strings.Contains(string(data), "还有 3 条告警未显示") {
+		t.Errorf("card = %s", data)
+	}
+}
@@ -76,5 +76,21 @@ func main() {
 		log.Printf("发送交互式卡片失败: %v", err)
 	}
 
+	// 示例7: 使用卡片构建器发送交互式卡片
+	card := feishu.NewCardBuilder().
+		Config(feishu.CreateCardConfig(true)).
+		Header("部署完成通知", "green").
+		Div(feishu.NewLarkMd("**应用部署成功**"),
+			feishu.NewField(true, feishu.NewLarkMd("**项目:**\nmyapp")),
+			feishu.NewField(true, feishu.NewLarkMd("**环境:**\nproduction")),
+		).
+		Hr().
+		Action(feishu.NewButton("查看详情", feishu.ButtonPrimary).WithURL("https://deploy.example.com"))
+
+	err = sdk.SendCard(card)
+	if err != nil {
+		log.Printf("发送卡片失败: %v", err)
+	}
+
 	fmt.Println("所有示例执行完成!")
 }